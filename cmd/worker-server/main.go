@@ -9,6 +9,8 @@ import (
 	"github.com/g0ulartleo/mirante-alerts/internal/config"
 	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
 	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/builtins"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/plugin"
 	"github.com/g0ulartleo/mirante-alerts/internal/signal"
 	signalrepo "github.com/g0ulartleo/mirante-alerts/internal/signal/repo"
 	"github.com/g0ulartleo/mirante-alerts/internal/worker"
@@ -30,6 +32,12 @@ func main() {
 
 	sentinelFactory := sentinel.NewFactory()
 	builtins.Register(sentinelFactory)
+	builtins.SetExecAllowlist(config.Env().ExecAllowlist)
+	if pluginDir := config.Env().SentinelPluginDir; pluginDir != "" {
+		if err := plugin.Register(sentinelFactory, pluginDir); err != nil {
+			log.Fatalf("Error loading sentinel plugins: %v", err)
+		}
+	}
 
 	signalRepo, err := signalrepo.New(config.LoadAppConfigFromEnv())
 	if err != nil {
@@ -45,6 +53,7 @@ func main() {
 		Addr: config.Env().RedisAddr,
 	})
 	defer redisClient.Close()
+	connections.SetHostKeyRepository(connections.NewRedisHostKeyRepository(redisClient))
 
 	srv := asynq.NewServer(
 		asynq.RedisClientOpt{Addr: config.Env().RedisAddr},
@@ -48,6 +48,14 @@ func LoadAlarmConfig(path string) (*Alarm, error) {
 		}
 		alarm.Cron = fmt.Sprintf("@every %s", interval)
 	}
+	if err := alarm.Notifications.Validate(); err != nil {
+		return nil, fmt.Errorf("misconfiguration for alarm %s: notifications: %w", alarm.ID, err)
+	}
+	if alarm.IsHeartbeat() {
+		if _, err := alarm.HeartbeatTimeout(); err != nil {
+			return nil, fmt.Errorf("misconfiguration for alarm %s: %w", alarm.ID, err)
+		}
+	}
 	if len(alarm.Path) == 0 {
 		alarm.Path = strings.Split(path, "/")[2 : len(strings.Split(path, "/"))-1]
 	}
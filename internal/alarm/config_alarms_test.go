@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -125,6 +126,65 @@ type: endpoint-checker
 interval: invalid
 config:
   url: https://example.com
+`,
+			expectError: true,
+		},
+		{
+			name: "notifications routed by status",
+			yamlContent: `
+id: test-alarm
+name: Test Alarm
+description: Test alarm configuration
+type: endpoint-checker
+interval: 30s
+config:
+  url: https://example.com
+notifications:
+  email:
+    to:
+      - test@example.com
+    statuses: [warning, unhealthy, healthy]
+  slack:
+    webhook_url: https://hooks.slack.com/services/T00000000/B00000000/XXXXXXXXX
+    statuses: [unhealthy, healthy]
+`,
+			expectedAlarm: &Alarm{
+				ID:          "test-alarm",
+				Name:        "Test Alarm",
+				Description: "Test alarm configuration",
+				Type:        "endpoint-checker",
+				Interval:    "30s",
+				Cron:        "@every 30s",
+				Config: map[string]any{
+					"url": "https://example.com",
+				},
+				Notifications: AlarmNotifications{
+					Email: EmailNotificationConfig{
+						To:       []string{"test@example.com"},
+						Statuses: []signal.Status{signal.StatusWarning, signal.StatusUnhealthy, signal.StatusHealthy},
+					},
+					Slack: SlackNotificationConfig{
+						WebhookURL: "https://hooks.slack.com/services/T00000000/B00000000/XXXXXXXXX",
+						Statuses:   []signal.Status{signal.StatusUnhealthy, signal.StatusHealthy},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "notifications routed to an invalid status",
+			yamlContent: `
+id: test-alarm
+name: Test Alarm
+description: Test alarm configuration
+type: endpoint-checker
+interval: 30s
+config:
+  url: https://example.com
+notifications:
+  slack:
+    webhook_url: https://hooks.slack.com/services/T00000000/B00000000/XXXXXXXXX
+    statuses: [critical]
 `,
 			expectError: true,
 		},
@@ -180,3 +240,9 @@ func writeAlarmConfig(t *testing.T, yamlContent string) string {
 
 	return tmpFile
 }
+
+func TestNotifiesStatus(t *testing.T) {
+	assert.True(t, NotifiesStatus(nil, signal.StatusWarning))
+	assert.True(t, NotifiesStatus([]signal.Status{signal.StatusUnhealthy, signal.StatusHealthy}, signal.StatusUnhealthy))
+	assert.False(t, NotifiesStatus([]signal.Status{signal.StatusUnhealthy, signal.StatusHealthy}, signal.StatusWarning))
+}
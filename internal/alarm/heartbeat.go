@@ -0,0 +1,64 @@
+package alarm
+
+import (
+	"fmt"
+	"time"
+)
+
+// TypeHeartbeat alarms are pushed to instead of probed: the monitored job pings
+// the heartbeat API and the alarm turns unhealthy when pings stop arriving.
+const TypeHeartbeat = "heartbeat"
+
+func (a *Alarm) IsHeartbeat() bool {
+	return a.Type == TypeHeartbeat
+}
+
+// HeartbeatTimeout is how long a heartbeat alarm waits for a ping before it is
+// considered stale: the expected period between pings plus the grace period.
+// The period defaults to the alarm's interval and can be set with
+// `config.period`, e.g. for cron-scheduled alarms.
+//
+// Staleness is only evaluated when the alarm's scheduled check runs, so a
+// missed ping is reported up to one check interval (or cron period) after the
+// timeout elapses. Schedule the check more often than the period to notice it
+// sooner.
+func (a *Alarm) HeartbeatTimeout() (time.Duration, error) {
+	period, err := heartbeatDuration(a.Config, "period", 0)
+	if err != nil {
+		return 0, err
+	}
+	if period == 0 {
+		if a.Interval == "" {
+			return 0, fmt.Errorf("heartbeat alarms require an interval or config.period")
+		}
+		if period, err = time.ParseDuration(a.Interval); err != nil {
+			return 0, fmt.Errorf("failed to parse interval: %w", err)
+		}
+	}
+	grace, err := heartbeatDuration(a.Config, "grace", 0)
+	if err != nil {
+		return 0, err
+	}
+	return period + grace, nil
+}
+
+func heartbeatDuration(config map[string]any, field string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := config[field]
+	if !ok {
+		return defaultValue, nil
+	}
+	switch v := value.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse config.%s: %w", field, err)
+		}
+		return d, nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("config.%s must be a duration: %v", field, value)
+	}
+}
@@ -0,0 +1,55 @@
+package alarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarm_HeartbeatTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		alarm           Alarm
+		expectedTimeout time.Duration
+		expectError     bool
+	}{
+		{
+			name:            "interval without grace",
+			alarm:           Alarm{Type: TypeHeartbeat, Interval: "1h"},
+			expectedTimeout: time.Hour,
+		},
+		{
+			name:            "interval with grace",
+			alarm:           Alarm{Type: TypeHeartbeat, Interval: "1h", Config: map[string]any{"grace": "10m"}},
+			expectedTimeout: 70 * time.Minute,
+		},
+		{
+			name:            "period overrides interval",
+			alarm:           Alarm{Type: TypeHeartbeat, Cron: "0 2 * * *", Config: map[string]any{"period": "24h", "grace": 1800}},
+			expectedTimeout: 24*time.Hour + 30*time.Minute,
+		},
+		{
+			name:        "cron without period",
+			alarm:       Alarm{Type: TypeHeartbeat, Cron: "0 2 * * *"},
+			expectError: true,
+		},
+		{
+			name:        "invalid grace",
+			alarm:       Alarm{Type: TypeHeartbeat, Interval: "1h", Config: map[string]any{"grace": "soon"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, err := tt.alarm.HeartbeatTimeout()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTimeout, timeout)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package repo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
 	"github.com/g0ulartleo/mirante-alerts/internal/config"
@@ -49,6 +51,9 @@ func (r *RedisAlarmRepository) GetAlarms() ([]*alarm.Alarm, error) {
 func (r *RedisAlarmRepository) GetAlarm(alarmID string) (*alarm.Alarm, error) {
 	key := fmt.Sprintf("alarm:%s", alarmID)
 	result, err := r.redis.Get(context.Background(), key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", alarm.ErrAlarmNotFound, alarmID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -73,12 +78,30 @@ func (r *RedisAlarmRepository) SetAlarm(a *alarm.Alarm) error {
 
 func (r *RedisAlarmRepository) DeleteAlarm(alarmID string) error {
 	key := fmt.Sprintf("alarm:%s", alarmID)
-	if err := r.redis.Del(context.Background(), key).Err(); err != nil {
+	heartbeatKey := fmt.Sprintf("heartbeat:%s", alarmID)
+	if err := r.redis.Del(context.Background(), key, heartbeatKey).Err(); err != nil {
 		return err
 	}
 	return r.redis.Save(context.Background()).Err()
 }
 
+func (r *RedisAlarmRepository) SetLastHeartbeat(alarmID string, at time.Time) error {
+	key := fmt.Sprintf("heartbeat:%s", alarmID)
+	return r.redis.Set(context.Background(), key, at.UTC().Format(time.RFC3339Nano), 0).Err()
+}
+
+func (r *RedisAlarmRepository) GetLastHeartbeat(alarmID string) (time.Time, error) {
+	key := fmt.Sprintf("heartbeat:%s", alarmID)
+	result, err := r.redis.Get(context.Background(), key).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, result)
+}
+
 func (r *RedisAlarmRepository) Close() error {
 	return r.redis.Close()
 }
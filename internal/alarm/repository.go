@@ -1,10 +1,21 @@
 package alarm
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrAlarmNotFound is returned by GetAlarm when no alarm has the given ID.
+var ErrAlarmNotFound = errors.New("alarm not found")
+
 type AlarmRepository interface {
 	Init() error
 	GetAlarms() ([]*Alarm, error)
 	GetAlarm(alarmID string) (*Alarm, error)
 	SetAlarm(alarm *Alarm) error
 	DeleteAlarm(alarmID string) error
+	SetLastHeartbeat(alarmID string, at time.Time) error
+	// GetLastHeartbeat returns the zero time if the alarm was never pinged.
+	GetLastHeartbeat(alarmID string) (time.Time, error)
 	Close() error
 }
@@ -1,5 +1,7 @@
 package alarm
 
+import "time"
+
 type AlarmService struct {
 	repo AlarmRepository
 }
@@ -27,3 +29,11 @@ func (s *AlarmService) SetAlarm(alarm *Alarm) error {
 func (s *AlarmService) DeleteAlarm(id string) error {
 	return s.repo.DeleteAlarm(id)
 }
+
+func (s *AlarmService) RecordHeartbeat(id string, at time.Time) error {
+	return s.repo.SetLastHeartbeat(id, at)
+}
+
+func (s *AlarmService) GetLastHeartbeat(id string) (time.Time, error) {
+	return s.repo.GetLastHeartbeat(id)
+}
@@ -1,6 +1,11 @@
 package alarm
 
-import "github.com/g0ulartleo/mirante-alerts/internal/signal"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
 
 type Alarm struct {
 	ID            string             `yaml:"id"`
@@ -24,12 +29,40 @@ type AlarmNotifications struct {
 	NotifyMissingSignals bool                    `yaml:"notify_missing_signals"`
 }
 
+// Validate checks that every channel only routes known statuses.
+func (n AlarmNotifications) Validate() error {
+	if err := validateStatuses(n.Email.Statuses); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := validateStatuses(n.Slack.Statuses); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+func validateStatuses(statuses []signal.Status) error {
+	for _, status := range statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("invalid status %s, expected one of %v", status, signal.Statuses)
+		}
+	}
+	return nil
+}
+
+// NotifiesStatus reports whether a channel routed to the given statuses
+// should be notified of a signal. An empty list routes every status.
+func NotifiesStatus(statuses []signal.Status, status signal.Status) bool {
+	return len(statuses) == 0 || slices.Contains(statuses, status)
+}
+
 type EmailNotificationConfig struct {
-	To []string `yaml:"to"`
+	To       []string        `yaml:"to"`
+	Statuses []signal.Status `yaml:"statuses"`
 }
 
 type SlackNotificationConfig struct {
-	WebhookURL string `yaml:"webhook_url"`
+	WebhookURL string          `yaml:"webhook_url"`
+	Statuses   []signal.Status `yaml:"statuses"`
 }
 
 type AlarmSignals struct {
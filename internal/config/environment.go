@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -24,6 +25,8 @@ type Environment struct {
 	OAuthClientID     string
 	OAuthClientSecret string
 	OAuthJWTSecret    string
+	SentinelPluginDir string
+	ExecAllowlist     []string
 }
 
 var (
@@ -43,6 +46,17 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return value
 }
 
+// getEnvList splits a comma separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func Env() *Environment {
 	once.Do(func() {
 		env = &Environment{
@@ -64,6 +78,8 @@ func Env() *Environment {
 			OAuthClientID:     os.Getenv("OAUTH_CLIENT_ID"),
 			OAuthClientSecret: os.Getenv("OAUTH_CLIENT_SECRET"),
 			OAuthJWTSecret:    os.Getenv("OAUTH_JWT_SECRET"),
+			SentinelPluginDir: os.Getenv("SENTINEL_PLUGIN_DIR"),
+			ExecAllowlist:     getEnvList("EXEC_SENTINEL_ALLOWLIST"),
 		}
 	})
 
@@ -7,10 +7,11 @@ import (
 
 func Dispatch(alarmConfig *alarm.Alarm, sig signal.Signal) []error {
 	notifications := []Notification{}
-	if len(alarmConfig.Notifications.Email.To) > 0 {
+	email, slack := alarmConfig.Notifications.Email, alarmConfig.Notifications.Slack
+	if len(email.To) > 0 && alarm.NotifiesStatus(email.Statuses, sig.Status) {
 		notifications = append(notifications, NewEmailNotification())
 	}
-	if alarmConfig.Notifications.Slack.WebhookURL != "" {
+	if slack.WebhookURL != "" && alarm.NotifiesStatus(slack.Statuses, sig.Status) {
 		notifications = append(notifications, NewSlackNotification())
 	}
 
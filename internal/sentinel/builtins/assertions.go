@@ -0,0 +1,227 @@
+package builtins
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// valueAssertion checks a single value extracted from a response, such as a
+// JSON field or a header. Every configured operator must hold.
+type valueAssertion struct {
+	target   string
+	exists   *bool
+	equals   any
+	contains string
+	regex    *regexp.Regexp
+	gt       *float64
+	gte      *float64
+	lt       *float64
+	lte      *float64
+}
+
+var numericAssertionOperators = []string{"gt", "gte", "lt", "lte"}
+
+func parseValueAssertions(config map[string]any, field string, targetKey string) ([]valueAssertion, error) {
+	raw, ok := config[field]
+	if !ok {
+		return nil, nil
+	}
+	entries, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", field)
+	}
+	assertions := make([]valueAssertion, 0, len(entries))
+	for i, entry := range entries {
+		entryConfig, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a map", field, i)
+		}
+		assertion, err := parseValueAssertion(entryConfig, targetKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %v", field, i, err)
+		}
+		assertions = append(assertions, assertion)
+	}
+	return assertions, nil
+}
+
+func parseValueAssertion(config map[string]any, targetKey string) (valueAssertion, error) {
+	var a valueAssertion
+	var err error
+	if _, ok := config[targetKey]; !ok {
+		return a, fmt.Errorf("missing required field: %s", targetKey)
+	}
+	if a.target, err = getString(config, targetKey, ""); err != nil {
+		return a, err
+	}
+	if _, ok := config["exists"]; ok {
+		exists, err := getBool(config, "exists", true)
+		if err != nil {
+			return a, err
+		}
+		a.exists = &exists
+	}
+	a.equals = config["equals"]
+	if a.contains, err = getString(config, "contains", ""); err != nil {
+		return a, err
+	}
+	pattern, err := getString(config, "regex", "")
+	if err != nil {
+		return a, err
+	}
+	if pattern != "" {
+		if a.regex, err = regexp.Compile(pattern); err != nil {
+			return a, fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+	for _, op := range numericAssertionOperators {
+		value, ok := config[op]
+		if !ok {
+			continue
+		}
+		n, ok := toFloat64(value)
+		if !ok {
+			return a, fmt.Errorf("can't convert `%s` to number: %v", op, value)
+		}
+		switch op {
+		case "gt":
+			a.gt = &n
+		case "gte":
+			a.gte = &n
+		case "lt":
+			a.lt = &n
+		case "lte":
+			a.lte = &n
+		}
+	}
+	return a, nil
+}
+
+func (a valueAssertion) evaluate(value any, found bool) error {
+	if a.exists != nil {
+		if *a.exists && !found {
+			return fmt.Errorf("%s is missing", a.target)
+		}
+		if !*a.exists && found {
+			return fmt.Errorf("%s is present", a.target)
+		}
+	}
+	if !found {
+		if a.equals != nil || a.contains != "" || a.regex != nil || a.gt != nil || a.gte != nil || a.lt != nil || a.lte != nil {
+			return fmt.Errorf("%s is missing", a.target)
+		}
+		return nil
+	}
+
+	str := stringifyValue(value)
+	if a.equals != nil && !valuesEqual(value, a.equals) {
+		return fmt.Errorf("%s is %s, expected %s", a.target, str, stringifyValue(a.equals))
+	}
+	if a.contains != "" && !strings.Contains(str, a.contains) {
+		return fmt.Errorf("%s is %s, expected it to contain %s", a.target, str, a.contains)
+	}
+	if a.regex != nil && !a.regex.MatchString(str) {
+		return fmt.Errorf("%s is %s, expected it to match %s", a.target, str, a.regex.String())
+	}
+	if a.gt == nil && a.gte == nil && a.lt == nil && a.lte == nil {
+		return nil
+	}
+	n, ok := toFloat64(value)
+	if !ok {
+		return fmt.Errorf("%s is %s, expected a number", a.target, str)
+	}
+	if a.gt != nil && !(n > *a.gt) {
+		return fmt.Errorf("%s is %v, expected > %v", a.target, n, *a.gt)
+	}
+	if a.gte != nil && !(n >= *a.gte) {
+		return fmt.Errorf("%s is %v, expected >= %v", a.target, n, *a.gte)
+	}
+	if a.lt != nil && !(n < *a.lt) {
+		return fmt.Errorf("%s is %v, expected < %v", a.target, n, *a.lt)
+	}
+	if a.lte != nil && !(n <= *a.lte) {
+		return fmt.Errorf("%s is %v, expected <= %v", a.target, n, *a.lte)
+	}
+	return nil
+}
+
+func valuesEqual(actual any, expected any) bool {
+	actualNumber, actualIsNumber := actual.(float64)
+	if expectedNumber, ok := toFloat64(expected); ok && actualIsNumber {
+		return actualNumber == expectedNumber
+	}
+	return stringifyValue(actual) == stringifyValue(expected)
+}
+
+func stringifyValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any, []any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// lookupJSONPath resolves a gjson-style dotted path such as `data.items.0.id`
+// against a decoded JSON document. A `#` segment returns the length of an
+// array or object.
+func lookupJSONPath(document any, path string) (any, bool) {
+	current := document
+	if path == "" {
+		return current, true
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			if segment == "#" {
+				current = float64(len(node))
+				continue
+			}
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			if segment == "#" {
+				current = float64(len(node))
+				continue
+			}
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
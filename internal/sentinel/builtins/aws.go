@@ -0,0 +1,91 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// loadAWSConfig builds an AWS config from the `aws_region`, `aws_access_key_id`,
+// `aws_secret_access_key`, `aws_session_token`, `assume_role_arn` and
+// `assume_role_external_id` config fields shared by the AWS based sentinels.
+// Without explicit keys the default credential chain is used.
+//
+// The `endpoint_url` field is returned separately rather than set on the
+// config, so that it only applies to the service client it was meant for and
+// not to STS or CloudWatch.
+func loadAWSConfig(ctx context.Context, config map[string]any) (aws.Config, string, error) {
+	region, err := getString(config, "aws_region", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	if region == "" {
+		return aws.Config{}, "", fmt.Errorf("missing required field: aws_region")
+	}
+	accessKeyID, err := getString(config, "aws_access_key_id", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	secretAccessKey, err := getString(config, "aws_secret_access_key", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	sessionToken, err := getString(config, "aws_session_token", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	if (accessKeyID == "") != (secretAccessKey == "") {
+		return aws.Config{}, "", fmt.Errorf("aws_access_key_id and aws_secret_access_key must be set together")
+	}
+	roleARN, err := getString(config, "assume_role_arn", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	externalID, err := getString(config, "assume_role_external_id", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+	if externalID != "" && roleARN == "" {
+		return aws.Config{}, "", fmt.Errorf("assume_role_external_id requires assume_role_arn")
+	}
+	endpointURL, err := getString(config, "endpoint_url", "")
+	if err != nil {
+		return aws.Config{}, "", err
+	}
+
+	options := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if accessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken),
+		))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return aws.Config{}, "", fmt.Errorf("failed to create AWS config: %v", err)
+	}
+
+	if roleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "mirante-alerts"
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, endpointURL, nil
+}
+
+// baseEndpoint returns the value for a client's BaseEndpoint option, leaving
+// the default AWS endpoint in place when url is empty.
+func baseEndpoint(url string) *string {
+	if url == "" {
+		return nil
+	}
+	return aws.String(url)
+}
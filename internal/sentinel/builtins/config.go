@@ -0,0 +1,89 @@
+package builtins
+
+import (
+	"fmt"
+	"time"
+)
+
+func getString(config map[string]any, field string, defaultValue string) (string, error) {
+	value, ok := config[field]
+	if !ok {
+		return defaultValue, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("can't convert `%s` to string: %v", field, value)
+	}
+	return s, nil
+}
+
+func getInt64(config map[string]any, field string, defaultValue int64) (int64, error) {
+	value, ok := config[field]
+	if !ok {
+		return defaultValue, nil
+	}
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("can't convert `%s` to int64: %v", field, value)
+	}
+}
+
+func getBool(config map[string]any, field string, defaultValue bool) (bool, error) {
+	value, ok := config[field]
+	if !ok {
+		return defaultValue, nil
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("can't convert `%s` to bool: %v", field, value)
+	}
+	return b, nil
+}
+
+// getDuration accepts either a Go duration string ("10s", "1m30s") or a number of seconds.
+func getDuration(config map[string]any, field string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := config[field]
+	if !ok {
+		return defaultValue, nil
+	}
+	switch v := value.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("can't parse `%s` as duration: %v", field, err)
+		}
+		return d, nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("can't convert `%s` to duration: %v", field, value)
+	}
+}
+
+func getStringList(config map[string]any, field string) ([]string, error) {
+	value, ok := config[field]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", field)
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("can't convert `%s` item to string: %v", field, item)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
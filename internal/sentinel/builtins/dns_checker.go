@@ -0,0 +1,225 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const (
+	DefaultDNSRecordType = "A"
+	DefaultDNSTimeout    = 5 * time.Second
+)
+
+var supportedDNSRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "SRV"}
+
+type DNSCheckerSentinel struct {
+	name       string
+	recordType string
+	resolver   string
+	expected   []string
+	minAnswers int
+	timeout    time.Duration
+}
+
+func NewDNSCheckerSentinel() sentinel.Sentinel {
+	return &DNSCheckerSentinel{}
+}
+
+func (s *DNSCheckerSentinel) Configure(config map[string]any) error {
+	if _, ok := config["name"]; !ok {
+		return fmt.Errorf("missing required field: name")
+	}
+	var err error
+	s.name, err = getString(config, "name", "")
+	if err != nil {
+		return err
+	}
+
+	recordType, err := getString(config, "record_type", DefaultDNSRecordType)
+	if err != nil {
+		return err
+	}
+	s.recordType = strings.ToUpper(recordType)
+	if !slices.Contains(supportedDNSRecordTypes, s.recordType) {
+		return fmt.Errorf("unsupported record_type %s, expected one of %v", recordType, supportedDNSRecordTypes)
+	}
+
+	s.resolver, err = getString(config, "resolver", "")
+	if err != nil {
+		return err
+	}
+	if s.resolver != "" {
+		if _, _, err := net.SplitHostPort(s.resolver); err != nil {
+			s.resolver = net.JoinHostPort(s.resolver, "53")
+		}
+	}
+
+	if expected, ok := config["expected"]; ok {
+		values, ok := expected.([]any)
+		if !ok {
+			return fmt.Errorf("expected must be a list")
+		}
+		for _, v := range values {
+			str, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("can't convert `expected` entry to string: %v", v)
+			}
+			s.expected = append(s.expected, normalizeDNSAnswer(s.recordType, str))
+		}
+	}
+
+	minAnswers, err := getInt64(config, "min_answers", 1)
+	if err != nil {
+		return err
+	}
+	s.minAnswers = int(minAnswers)
+
+	s.timeout, err = getDuration(config, "timeout", DefaultDNSTimeout)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *DNSCheckerSentinel) newResolver() *net.Resolver {
+	if s.resolver == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: s.timeout}
+			return dialer.DialContext(ctx, network, s.resolver)
+		},
+	}
+}
+
+func (s *DNSCheckerSentinel) lookup(ctx context.Context) ([]string, error) {
+	resolver := s.newResolver()
+	answers := make([]string, 0)
+	switch s.recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if s.recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, s.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, s.name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := resolver.LookupMX(ctx, s.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, s.name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	case "SRV":
+		_, records, err := resolver.LookupSRV(ctx, "", "", s.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range records {
+			answers = append(answers, net.JoinHostPort(srv.Target, strconv.Itoa(int(srv.Port))))
+		}
+	}
+	for i := range answers {
+		answers[i] = normalizeDNSAnswer(s.recordType, answers[i])
+	}
+	slices.Sort(answers)
+	return answers, nil
+}
+
+func normalizeDNSAnswer(recordType string, answer string) string {
+	switch recordType {
+	case "A", "AAAA":
+		if ip := net.ParseIP(answer); ip != nil {
+			return ip.String()
+		}
+	case "CNAME", "MX":
+		return strings.ToLower(strings.TrimSuffix(answer, "."))
+	case "SRV":
+		if host, port, err := net.SplitHostPort(answer); err == nil {
+			return net.JoinHostPort(strings.ToLower(strings.TrimSuffix(host, ".")), port)
+		}
+	}
+	return answer
+}
+
+func (s *DNSCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	answers, err := s.lookup(ctx)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnhealthy,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("%s record for %s not found", s.recordType, s.name),
+			}, nil
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to resolve %s record for %s: %v", s.recordType, s.name, err),
+		}, nil
+	}
+
+	if len(s.expected) > 0 {
+		expected := slices.Clone(s.expected)
+		slices.Sort(expected)
+		if !slices.Equal(expected, answers) {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnhealthy,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("%s %s resolved to %v, expected %v", s.name, s.recordType, answers, expected),
+			}, nil
+		}
+	}
+
+	if len(answers) < s.minAnswers {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("%s %s returned %d answers, expected at least %d", s.name, s.recordType, len(answers), s.minAnswers),
+		}, nil
+	}
+
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusHealthy,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("%s %s resolved to %v", s.name, s.recordType, answers),
+	}, nil
+}
@@ -0,0 +1,231 @@
+package builtins
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type fakeDNSRecord struct {
+	name string
+	body dnsmessage.ResourceBody
+}
+
+func fakeDNSRecordType(body dnsmessage.ResourceBody) dnsmessage.Type {
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		return dnsmessage.TypeAAAA
+	case *dnsmessage.CNAMEResource:
+		return dnsmessage.TypeCNAME
+	case *dnsmessage.MXResource:
+		return dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		return dnsmessage.TypeTXT
+	case *dnsmessage.SRVResource:
+		return dnsmessage.TypeSRV
+	}
+	return 0
+}
+
+func startDNSServer(t *testing.T, records []fakeDNSRecord) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var parser dnsmessage.Parser
+			header, err := parser.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			question, err := parser.Question()
+			if err != nil {
+				continue
+			}
+
+			answers := make([]fakeDNSRecord, 0)
+			for _, r := range records {
+				if r.name == question.Name.String() && fakeDNSRecordType(r.body) == question.Type {
+					answers = append(answers, r)
+				}
+			}
+			rcode := dnsmessage.RCodeSuccess
+			if len(answers) == 0 {
+				rcode = dnsmessage.RCodeNameError
+			}
+
+			builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+				ID:            header.ID,
+				Response:      true,
+				Authoritative: true,
+				RCode:         rcode,
+			})
+			builder.EnableCompression()
+			builder.StartQuestions()
+			builder.Question(question)
+			builder.StartAnswers()
+			for _, r := range answers {
+				rh := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+				switch body := r.body.(type) {
+				case *dnsmessage.AResource:
+					builder.AResource(rh, *body)
+				case *dnsmessage.AAAAResource:
+					builder.AAAAResource(rh, *body)
+				case *dnsmessage.CNAMEResource:
+					builder.CNAMEResource(rh, *body)
+				case *dnsmessage.MXResource:
+					builder.MXResource(rh, *body)
+				case *dnsmessage.TXTResource:
+					builder.TXTResource(rh, *body)
+				case *dnsmessage.SRVResource:
+					builder.SRVResource(rh, *body)
+				}
+			}
+			response, err := builder.Finish()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(response, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDNSCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name:        "valid configuration",
+			config:      map[string]any{"name": "example.com", "record_type": "mx", "resolver": "1.1.1.1"},
+			expectError: false,
+		},
+		{
+			name:        "missing name",
+			config:      map[string]any{"record_type": "A"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported record type",
+			config:      map[string]any{"name": "example.com", "record_type": "PTR"},
+			expectError: true,
+		},
+		{
+			name:        "expected is not a list",
+			config:      map[string]any{"name": "example.com", "expected": "10.0.0.1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &DNSCheckerSentinel{}
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "MX", s.recordType)
+			assert.Equal(t, "1.1.1.1:53", s.resolver)
+		})
+	}
+}
+
+func TestDNSCheckerSentinel_Check(t *testing.T) {
+	resolver := startDNSServer(t, []fakeDNSRecord{
+		{name: "api.mirante.test.", body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+		{name: "api.mirante.test.", body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}},
+		{name: "api.mirante.test.", body: &dnsmessage.AAAAResource{AAAA: [16]byte{0xfd, 15: 1}}},
+		{name: "www.mirante.test.", body: &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("api.mirante.test.")}},
+		{name: "mirante.test.", body: &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.mirante.test.")}},
+		{name: "mirante.test.", body: &dnsmessage.TXTResource{TXT: []string{"v=spf1 include:mail.mirante.test -all"}}},
+		{name: "_sip._tcp.mirante.test.", body: &dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 5060, Target: dnsmessage.MustNewName("sip.mirante.test.")}},
+	})
+
+	tests := []struct {
+		name           string
+		config         map[string]any
+		expectedStatus signal.Status
+	}{
+		{
+			name:           "healthy - A records match expected set",
+			config:         map[string]any{"name": "api.mirante.test", "expected": []any{"10.0.0.2", "10.0.0.1"}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - A records drifted",
+			config:         map[string]any{"name": "api.mirante.test", "expected": []any{"10.0.0.1", "10.0.0.3"}},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "healthy - minimum answer count",
+			config:         map[string]any{"name": "api.mirante.test", "min_answers": 2},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - below minimum answer count",
+			config:         map[string]any{"name": "api.mirante.test", "min_answers": 3},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "healthy - AAAA record",
+			config:         map[string]any{"name": "api.mirante.test", "record_type": "AAAA", "expected": []any{"fd00::1"}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - CNAME record",
+			config:         map[string]any{"name": "www.mirante.test", "record_type": "CNAME", "expected": []any{"api.mirante.test."}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - MX record",
+			config:         map[string]any{"name": "mirante.test", "record_type": "MX", "expected": []any{"mail.mirante.test"}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - TXT record",
+			config:         map[string]any{"name": "mirante.test", "record_type": "TXT", "expected": []any{"v=spf1 include:mail.mirante.test -all"}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - SRV record",
+			config:         map[string]any{"name": "_sip._tcp.mirante.test", "record_type": "SRV", "expected": []any{"sip.mirante.test:5060"}},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - name not found",
+			config:         map[string]any{"name": "missing.mirante.test"},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["resolver"] = resolver
+			s := &DNSCheckerSentinel{}
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.NotEmpty(t, sig.Message)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const (
+	DefaultDockerHost    = "unix:///var/run/docker.sock"
+	DefaultDockerTimeout = 10 * time.Second
+	// maxDockerResponseSize bounds how much of an inspect response is read.
+	maxDockerResponseSize = 4 << 20
+)
+
+type DockerContainerSentinel struct {
+	baseURL    string
+	containers []string
+	restarts   *thresholds
+	uptime     *thresholds
+	client     *http.Client
+}
+
+func NewDockerContainerSentinel() sentinel.Sentinel {
+	return &DockerContainerSentinel{}
+}
+
+func (d *DockerContainerSentinel) Configure(config map[string]any) error {
+	var err error
+	if d.containers, err = getStringList(config, "containers"); err != nil {
+		return err
+	}
+	if len(d.containers) == 0 {
+		return fmt.Errorf("missing required field: containers")
+	}
+	if d.restarts, err = parseNestedThresholds(config, "restarts", "<=", false); err != nil {
+		return err
+	}
+	if d.uptime, err = parseNestedThresholds(config, "uptime", ">=", true); err != nil {
+		return err
+	}
+	timeout, err := getDuration(config, "timeout", DefaultDockerTimeout)
+	if err != nil {
+		return err
+	}
+
+	host, err := getString(config, "host", DefaultDockerHost)
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("invalid host: %v", err)
+	}
+	transport := &http.Transport{}
+	switch endpoint.Scheme {
+	case "unix":
+		socket := endpoint.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		// The host part is ignored when dialing a socket.
+		d.baseURL = "http://docker"
+	case "tcp":
+		useTLS, err := getBool(config, "tls", false)
+		if err != nil {
+			return err
+		}
+		d.baseURL = "http://" + endpoint.Host
+		if useTLS {
+			if transport.TLSClientConfig, err = newTLSClientConfig(config); err != nil {
+				return err
+			}
+			d.baseURL = "https://" + endpoint.Host
+		}
+	default:
+		return fmt.Errorf("unsupported host %s, expected unix:// or tcp://", host)
+	}
+	d.client = &http.Client{Timeout: timeout, Transport: transport}
+	return nil
+}
+
+// dockerContainer holds the fields of the Engine API's container inspect
+// response used by the checks.
+type dockerContainer struct {
+	RestartCount int64 `json:"RestartCount"`
+	State        struct {
+		Status    string    `json:"Status"`
+		Running   bool      `json:"Running"`
+		ExitCode  int       `json:"ExitCode"`
+		StartedAt time.Time `json:"StartedAt"`
+		Health    *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				Output string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+func (d *DockerContainerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	worst := thresholdOK
+	var observations, failures []string
+	metrics := make(map[string]float64)
+	fail := func(level thresholdLevel, description string) {
+		worst = max(worst, level)
+		failures = append(failures, fmt.Sprintf("%s: %s", level, description))
+	}
+	evaluate := func(t *thresholds, value float64, description string) {
+		if t == nil {
+			return
+		}
+		if level := t.evaluate(value); level != thresholdOK {
+			fail(level, fmt.Sprintf("%s, %s", description, t.describe(level)))
+		}
+	}
+
+	for _, name := range d.containers {
+		container, found, err := d.inspect(ctx, name)
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   err.Error(),
+			}, nil
+		}
+		if !found {
+			fail(thresholdCritical, fmt.Sprintf("container %s not found", name))
+			continue
+		}
+		metrics["restarts:"+name] = float64(container.RestartCount)
+		if !container.State.Running || container.State.Status != "running" {
+			description := fmt.Sprintf("%s is %s", name, container.State.Status)
+			if container.State.Status == "exited" {
+				description += fmt.Sprintf(" with code %d", container.State.ExitCode)
+			}
+			fail(thresholdCritical, description)
+			continue
+		}
+
+		uptime := time.Since(container.State.StartedAt)
+		observation := fmt.Sprintf("%s running for %v", name, uptime.Round(time.Second))
+		if health := container.State.Health; health != nil {
+			observation += ", " + health.Status
+			switch health.Status {
+			case "unhealthy":
+				description := fmt.Sprintf("%s is unhealthy after %d failed checks", name, health.FailingStreak)
+				if len(health.Log) > 0 {
+					if output := strings.TrimSpace(health.Log[len(health.Log)-1].Output); output != "" {
+						description += ": " + output
+					}
+				}
+				fail(thresholdCritical, description)
+			case "starting":
+				fail(thresholdWarning, fmt.Sprintf("%s health check is starting", name))
+			}
+		}
+		observation += fmt.Sprintf(", %d restarts", container.RestartCount)
+		observations = append(observations, observation)
+		metrics["uptime_seconds:"+name] = uptime.Seconds()
+		evaluate(d.restarts, float64(container.RestartCount), fmt.Sprintf("%s restarted %d times", name, container.RestartCount))
+		evaluate(d.uptime, uptime.Seconds(), fmt.Sprintf("%s started %v ago", name, uptime.Round(time.Second)))
+	}
+
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = strings.Join(observations, "; ")
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+	}, nil
+}
+
+func (d *DockerContainerSentinel) inspect(ctx context.Context, name string) (dockerContainer, bool, error) {
+	var container dockerContainer
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/containers/%s/json", d.baseURL, url.PathEscape(name)), nil)
+	if err != nil {
+		return container, false, fmt.Errorf("failed to build request: %v", err)
+	}
+	response, err := d.client.Do(req)
+	if err != nil {
+		return container, false, fmt.Errorf("failed to reach the Docker Engine API: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxDockerResponseSize))
+	if err != nil {
+		return container, false, fmt.Errorf("failed to read response: %v", err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return container, false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiError) == nil && apiError.Message != "" {
+			return container, false, fmt.Errorf("failed to inspect %s: %s", name, apiError.Message)
+		}
+		return container, false, fmt.Errorf("failed to inspect %s: status %d", name, response.StatusCode)
+	}
+	if err := json.Unmarshal(body, &container); err != nil {
+		return container, false, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return container, true, nil
+}
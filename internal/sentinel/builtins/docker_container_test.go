@@ -0,0 +1,195 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeDockerEngine serves container inspect responses on a unix socket and
+// returns its `unix://` host.
+func startFakeDockerEngine(t *testing.T) string {
+	started := func(ago time.Duration) string {
+		return time.Now().Add(-ago).UTC().Format(time.RFC3339Nano)
+	}
+	containers := map[string]string{
+		"web": fmt.Sprintf(`{"Name":"/web","RestartCount":0,"State":{"Status":"running","Running":true,"StartedAt":%q,
+			"Health":{"Status":"healthy","FailingStreak":0,"Log":[{"ExitCode":0,"Output":"ok"}]}}}`, started(3*time.Hour)),
+		"worker": fmt.Sprintf(`{"Name":"/worker","RestartCount":4,"State":{"Status":"running","Running":true,"StartedAt":%q}}`, started(2*time.Minute)),
+		"redis": fmt.Sprintf(`{"Name":"/redis","RestartCount":0,"State":{"Status":"running","Running":true,"StartedAt":%q,
+			"Health":{"Status":"unhealthy","FailingStreak":3,"Log":[{"ExitCode":1,"Output":"Could not connect\n"},{"ExitCode":1,"Output":"LOADING Redis is loading the dataset in memory\n"}]}}}`, started(time.Hour)),
+		"scheduler": `{"Name":"/scheduler","RestartCount":1,"State":{"Status":"exited","Running":false,"ExitCode":137,"StartedAt":"2025-06-01T10:00:00Z"}}`,
+		"migrate": fmt.Sprintf(`{"Name":"/migrate","RestartCount":0,"State":{"Status":"running","Running":true,"StartedAt":%q,
+			"Health":{"Status":"starting","FailingStreak":0,"Log":[]}}}`, started(10*time.Second)),
+	}
+
+	// Unix socket paths are limited to about 100 bytes, too short for t.TempDir().
+	dir, err := os.MkdirTemp("", "docker")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/{name}/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		response, ok := containers[r.PathValue("name")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"message":"No such container: %s"}`, r.PathValue("name"))
+			return
+		}
+		w.Write([]byte(response))
+	})
+	server := httptest.NewUnstartedServer(mux)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return "unix://" + socket
+}
+
+func TestDockerContainerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"containers": []any{"web", "worker"},
+				"restarts":   map[string]any{"critical": 5, "warning": 0},
+				"uptime":     map[string]any{"critical": "1m", "warning": "10m"},
+			},
+			expectError: false,
+		},
+		{
+			name: "tcp host with TLS",
+			config: map[string]any{
+				"host":       "tcp://docker.internal:2376",
+				"tls":        true,
+				"containers": []any{"web"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing containers",
+			config:      map[string]any{"host": "unix:///var/run/docker.sock"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported host",
+			config:      map[string]any{"host": "ssh://docker.internal", "containers": []any{"web"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDockerContainerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDockerContainerSentinel_Check(t *testing.T) {
+	host := startFakeDockerEngine(t)
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name:            "running and healthy",
+			config:          map[string]any{"containers": []any{"web"}, "restarts": map[string]any{"critical": 0}},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "web running for 3h0m0s, healthy, 0 restarts",
+			expectedMetrics: map[string]float64{"restarts:web": 0, "uptime_seconds:web": 10800},
+		},
+		{
+			name: "restart loop",
+			config: map[string]any{
+				"containers": []any{"web", "worker"},
+				"restarts":   map[string]any{"critical": 3},
+				"uptime":     map[string]any{"critical": "1m", "warning": "10m"},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: worker restarted 4 times, expected <= 3; warning: worker started 2m0s ago, expected >= 600",
+			expectedMetrics: map[string]float64{
+				"restarts:web": 0, "uptime_seconds:web": 10800,
+				"restarts:worker": 4, "uptime_seconds:worker": 120,
+			},
+		},
+		{
+			name:            "failing health check",
+			config:          map[string]any{"containers": []any{"redis"}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: redis is unhealthy after 3 failed checks: LOADING Redis is loading the dataset in memory",
+			expectedMetrics: map[string]float64{"restarts:redis": 0, "uptime_seconds:redis": 3600},
+		},
+		{
+			name:            "health check starting",
+			config:          map[string]any{"containers": []any{"migrate"}},
+			expectedStatus:  signal.StatusWarning,
+			expectedMessage: "warning: migrate health check is starting",
+			expectedMetrics: map[string]float64{"restarts:migrate": 0, "uptime_seconds:migrate": 10},
+		},
+		{
+			name:            "stopped and missing containers",
+			config:          map[string]any{"containers": []any{"scheduler", "web", "cron"}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: scheduler is exited with code 137; critical: container cron not found",
+			expectedMetrics: map[string]float64{"restarts:scheduler": 1, "restarts:web": 0, "uptime_seconds:web": 10800},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["host"] = host
+			s := NewDockerContainerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			// Uptimes are measured from the fake start times, so allow for drift.
+			assert.Len(t, sig.Metrics, len(tt.expectedMetrics))
+			for name, value := range tt.expectedMetrics {
+				assert.InDelta(t, value, sig.Metrics[name], 5, name)
+			}
+		})
+	}
+}
+
+func TestDockerContainerSentinel_EngineUnavailable(t *testing.T) {
+	s := NewDockerContainerSentinel()
+	require.NoError(t, s.Configure(map[string]any{
+		"host":       "unix://" + filepath.Join(t.TempDir(), "missing.sock"),
+		"containers": []any{"web"},
+	}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusUnknown, sig.Status)
+	assert.Contains(t, sig.Message, "failed to reach the Docker Engine API")
+}
@@ -0,0 +1,299 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// maxElasticsearchResponseSize bounds how much of a response is read.
+const maxElasticsearchResponseSize = 1 << 20
+
+// elasticsearchStatuses are the cluster health statuses, from best to worst.
+var elasticsearchStatuses = []string{"green", "yellow", "red"}
+
+type ElasticsearchCheckerSentinel struct {
+	url              string
+	clusterStatus    string
+	unassignedShards *thresholds
+	numberOfNodes    *thresholds
+	count            *elasticsearchCount
+	request          httpRequestConfig
+	client           *http.Client
+}
+
+// elasticsearchCount counts the documents of an index pattern whose time
+// field falls within the last `timeRange`, e.g. to detect stalled ingestion.
+type elasticsearchCount struct {
+	index      string
+	timeField  string
+	timeRange  time.Duration
+	query      string
+	thresholds thresholds
+}
+
+func NewElasticsearchCheckerSentinel() sentinel.Sentinel {
+	return &ElasticsearchCheckerSentinel{}
+}
+
+func (e *ElasticsearchCheckerSentinel) Configure(config map[string]any) error {
+	if _, ok := config["url"]; !ok {
+		return fmt.Errorf("missing required field: url")
+	}
+	baseURL, err := getString(config, "url", "")
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("url must use http or https: %s", baseURL)
+	}
+	e.url = endpoint.String()
+
+	if e.clusterStatus, err = getString(config, "cluster_status", ""); err != nil {
+		return err
+	}
+	if e.clusterStatus != "" && !slices.Contains(elasticsearchStatuses[:2], e.clusterStatus) {
+		return fmt.Errorf("unsupported cluster_status %s, expected green or yellow", e.clusterStatus)
+	}
+	if e.unassignedShards, err = parseNestedThresholds(config, "unassigned_shards", "<=", false); err != nil {
+		return err
+	}
+	if e.numberOfNodes, err = parseNestedThresholds(config, "number_of_nodes", ">=", false); err != nil {
+		return err
+	}
+	if raw, ok := config["count"]; ok {
+		countConfig, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("count must be a map")
+		}
+		if e.count, err = parseElasticsearchCount(countConfig); err != nil {
+			return fmt.Errorf("count: %v", err)
+		}
+	}
+	if e.clusterStatus == "" && e.unassignedShards == nil && e.numberOfNodes == nil && e.count == nil {
+		return fmt.Errorf("at least one of cluster_status, unassigned_shards, number_of_nodes or count is required")
+	}
+
+	if e.request, err = parseHTTPRequestConfig(config); err != nil {
+		return err
+	}
+	if e.request.method != http.MethodGet || e.request.body != "" {
+		return fmt.Errorf("method and body are not supported")
+	}
+	apiKey, err := getString(config, "api_key", "")
+	if err != nil {
+		return err
+	}
+	if apiKey != "" {
+		if e.request.username != "" || e.request.bearerToken != "" {
+			return fmt.Errorf("api_key cannot be combined with basic_auth or bearer_token")
+		}
+		e.request.headers["Authorization"] = "ApiKey " + apiKey
+	}
+	if e.client, err = newHTTPClient(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseElasticsearchCount(config map[string]any) (*elasticsearchCount, error) {
+	c := &elasticsearchCount{}
+	var err error
+	if c.index, err = getString(config, "index", ""); err != nil {
+		return nil, err
+	}
+	if c.index == "" {
+		return nil, fmt.Errorf("missing required field: index")
+	}
+	if c.timeField, err = getString(config, "time_field", "@timestamp"); err != nil {
+		return nil, err
+	}
+	if _, ok := config["range"]; !ok {
+		return nil, fmt.Errorf("missing required field: range")
+	}
+	if c.timeRange, err = getDuration(config, "range", 0); err != nil {
+		return nil, err
+	}
+	if c.timeRange < time.Second {
+		return nil, fmt.Errorf("range must be at least 1s")
+	}
+	if c.query, err = getString(config, "query", ""); err != nil {
+		return nil, err
+	}
+	if c.thresholds, err = parseThresholds(config, "critical", ">="); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (e *ElasticsearchCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	worst := thresholdOK
+	var observations, failures []string
+	metrics := make(map[string]float64)
+	labels := make(map[string]string)
+	fail := func(level thresholdLevel, description string) {
+		worst = max(worst, level)
+		failures = append(failures, fmt.Sprintf("%s: %s", level, description))
+	}
+	evaluate := func(t *thresholds, metric string, value float64, description string) {
+		if t == nil {
+			return
+		}
+		metrics[metric] = value
+		observations = append(observations, description)
+		if level := t.evaluate(value); level != thresholdOK {
+			fail(level, fmt.Sprintf("%s, %s", description, t.describe(level)))
+		}
+	}
+
+	if e.clusterStatus != "" || e.unassignedShards != nil || e.numberOfNodes != nil {
+		health, err := e.clusterHealth(ctx)
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   err.Error(),
+			}, nil
+		}
+		labels["cluster"] = health.ClusterName
+		metrics["unassigned_shards"] = float64(health.UnassignedShards)
+		metrics["nodes"] = float64(health.NumberOfNodes)
+		if e.clusterStatus != "" {
+			description := fmt.Sprintf("cluster %s is %s", health.ClusterName, health.Status)
+			observations = append(observations, description)
+			// Yellow clusters still have every primary shard allocated, so they only warn.
+			switch actual := slices.Index(elasticsearchStatuses, health.Status); {
+			case actual < 0 || health.Status == "red":
+				fail(thresholdCritical, description)
+			case actual > slices.Index(elasticsearchStatuses, e.clusterStatus):
+				fail(thresholdWarning, description)
+			}
+		}
+		evaluate(e.unassignedShards, "unassigned_shards", float64(health.UnassignedShards), fmt.Sprintf("%d unassigned shards", health.UnassignedShards))
+		evaluate(e.numberOfNodes, "nodes", float64(health.NumberOfNodes), fmt.Sprintf("%d nodes", health.NumberOfNodes))
+	}
+
+	if e.count != nil {
+		count, err := e.countDocuments(ctx)
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   err.Error(),
+			}, nil
+		}
+		evaluate(&e.count.thresholds, "documents", float64(count), fmt.Sprintf("%d documents in %s in the last %v", count, e.count.index, e.count.timeRange))
+	}
+
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = strings.Join(observations, ", ")
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+		Labels:    labels,
+	}, nil
+}
+
+type elasticsearchClusterHealth struct {
+	ClusterName      string `json:"cluster_name"`
+	Status           string `json:"status"`
+	NumberOfNodes    int64  `json:"number_of_nodes"`
+	UnassignedShards int64  `json:"unassigned_shards"`
+}
+
+// elasticsearchError is the error envelope of the Elasticsearch and OpenSearch
+// REST APIs.
+type elasticsearchError struct {
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+func (e *ElasticsearchCheckerSentinel) clusterHealth(ctx context.Context) (elasticsearchClusterHealth, error) {
+	var health elasticsearchClusterHealth
+	req, err := e.request.newRequest(ctx, e.url+"/_cluster/health")
+	if err != nil {
+		return health, fmt.Errorf("failed to build request: %v", err)
+	}
+	if err := e.do(req, &health); err != nil {
+		return health, fmt.Errorf("cluster health request failed: %v", err)
+	}
+	return health, nil
+}
+
+func (e *ElasticsearchCheckerSentinel) countDocuments(ctx context.Context) (int64, error) {
+	filters := []any{
+		map[string]any{"range": map[string]any{
+			e.count.timeField: map[string]any{"gte": fmt.Sprintf("now-%ds", int64(e.count.timeRange.Seconds()))},
+		}},
+	}
+	if e.count.query != "" {
+		filters = append(filters, map[string]any{"query_string": map[string]any{"query": e.count.query}})
+	}
+	body, err := json.Marshal(map[string]any{"query": map[string]any{"bool": map[string]any{"filter": filters}}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode count query: %v", err)
+	}
+
+	request := e.request
+	request.method = http.MethodPost
+	request.body = string(body)
+	req, err := request.newRequest(ctx, e.url+"/"+url.PathEscape(e.count.index)+"/_count")
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	if err := e.do(req, &result); err != nil {
+		return 0, fmt.Errorf("count query failed: %v", err)
+	}
+	return result.Count, nil
+}
+
+// do sends the request and decodes a successful JSON response into v.
+func (e *ElasticsearchCheckerSentinel) do(req *http.Request, v any) error {
+	response, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxElasticsearchResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		var apiError elasticsearchError
+		if json.Unmarshal(body, &apiError) == nil && apiError.Error.Type != "" {
+			return fmt.Errorf("%s: %s", apiError.Error.Type, apiError.Error.Reason)
+		}
+		return fmt.Errorf("status %d", response.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newElasticsearchTestServer serves `_cluster/health` for the given cluster
+// status and `_count` for the `logs-*` pattern, which only has documents for
+// the api service.
+func newElasticsearchTestServer(t *testing.T, clusterStatus string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "ApiKey c2VjcmV0" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/_cluster/health":
+			unassigned := map[string]int{"green": 0, "yellow": 5, "red": 12}[clusterStatus]
+			fmt.Fprintf(w, `{"cluster_name":"logs","status":%q,"number_of_nodes":3,"unassigned_shards":%d}`, clusterStatus, unassigned)
+		case "/logs-*/_count":
+			var body struct {
+				Query struct {
+					Bool struct {
+						Filter []map[string]map[string]any `json:"filter"`
+					} `json:"bool"`
+				} `json:"query"`
+			}
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			filters := body.Query.Bool.Filter
+			require.Equal(t, map[string]any{"@timestamp": map[string]any{"gte": "now-600s"}}, filters[0]["range"])
+			count := 1500
+			if len(filters) > 1 && filters[1]["query_string"]["query"] != "service:api" {
+				count = 0
+			}
+			fmt.Fprintf(w, `{"count":%d,"_shards":{"total":3,"successful":3,"skipped":0,"failed":0}}`, count)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"type":"index_not_found_exception","reason":"no such index [metrics]"},"status":404}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestElasticsearchCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"url":               "https://es.internal:9200",
+				"cluster_status":    "green",
+				"unassigned_shards": map[string]any{"critical": 10, "warning": 0},
+				"number_of_nodes":   map[string]any{"critical": 3},
+				"count":             map[string]any{"index": "logs-*", "range": "10m", "critical": 1},
+				"api_key":           "c2VjcmV0",
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing url",
+			config:      map[string]any{"cluster_status": "green"},
+			expectError: true,
+		},
+		{
+			name:        "no checks",
+			config:      map[string]any{"url": "https://es.internal:9200"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported cluster status",
+			config:      map[string]any{"url": "https://es.internal:9200", "cluster_status": "red"},
+			expectError: true,
+		},
+		{
+			name:        "count without range",
+			config:      map[string]any{"url": "https://es.internal:9200", "count": map[string]any{"index": "logs-*", "critical": 1}},
+			expectError: true,
+		},
+		{
+			name: "api key and basic auth",
+			config: map[string]any{
+				"url":            "https://es.internal:9200",
+				"cluster_status": "green",
+				"api_key":        "c2VjcmV0",
+				"basic_auth":     map[string]any{"username": "elastic", "password": "secret"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewElasticsearchCheckerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestElasticsearchCheckerSentinel_Check(t *testing.T) {
+	tests := []struct {
+		name            string
+		clusterStatus   string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name:          "healthy cluster",
+			clusterStatus: "green",
+			config: map[string]any{
+				"cluster_status":    "green",
+				"unassigned_shards": map[string]any{"critical": 0},
+				"number_of_nodes":   map[string]any{"critical": 3},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "cluster logs is green, 0 unassigned shards, 3 nodes",
+			expectedMetrics: map[string]float64{"unassigned_shards": 0, "nodes": 3},
+		},
+		{
+			name:            "yellow cluster",
+			clusterStatus:   "yellow",
+			config:          map[string]any{"cluster_status": "green", "unassigned_shards": map[string]any{"critical": 10, "warning": 0}},
+			expectedStatus:  signal.StatusWarning,
+			expectedMessage: "warning: cluster logs is yellow; warning: 5 unassigned shards, expected <= 0",
+			expectedMetrics: map[string]float64{"unassigned_shards": 5, "nodes": 3},
+		},
+		{
+			name:            "yellow is accepted",
+			clusterStatus:   "yellow",
+			config:          map[string]any{"cluster_status": "yellow"},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "cluster logs is yellow",
+			expectedMetrics: map[string]float64{"unassigned_shards": 5, "nodes": 3},
+		},
+		{
+			name:            "red cluster",
+			clusterStatus:   "red",
+			config:          map[string]any{"cluster_status": "yellow", "number_of_nodes": map[string]any{"critical": 4}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: cluster logs is red; critical: 3 nodes, expected >= 4",
+			expectedMetrics: map[string]float64{"unassigned_shards": 12, "nodes": 3},
+		},
+		{
+			name:          "logs ingested",
+			clusterStatus: "green",
+			config: map[string]any{
+				"count": map[string]any{"index": "logs-*", "range": "10m", "query": "service:api", "critical": 1},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "1500 documents in logs-* in the last 10m0s",
+			expectedMetrics: map[string]float64{"documents": 1500},
+		},
+		{
+			name:          "no logs ingested",
+			clusterStatus: "green",
+			config: map[string]any{
+				"cluster_status": "green",
+				"count":          map[string]any{"index": "logs-*", "range": "10m", "query": "service:billing", "critical": 1},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: 0 documents in logs-* in the last 10m0s, expected >= 1",
+			expectedMetrics: map[string]float64{"unassigned_shards": 0, "nodes": 3, "documents": 0},
+		},
+		{
+			name:          "missing index",
+			clusterStatus: "green",
+			config: map[string]any{
+				"count": map[string]any{"index": "metrics", "range": "10m", "critical": 1},
+			},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "count query failed: index_not_found_exception: no such index [metrics]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["url"] = newElasticsearchTestServer(t, tt.clusterStatus).URL
+			tt.config["api_key"] = "c2VjcmV0"
+			s := NewElasticsearchCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+		})
+	}
+}
+
+func TestElasticsearchCheckerSentinel_Unauthorized(t *testing.T) {
+	s := NewElasticsearchCheckerSentinel()
+	require.NoError(t, s.Configure(map[string]any{
+		"url":            newElasticsearchTestServer(t, "green").URL,
+		"cluster_status": "green",
+	}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusUnknown, sig.Status)
+	assert.Equal(t, "cluster health request failed: status 401", sig.Message)
+}
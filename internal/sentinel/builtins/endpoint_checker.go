@@ -13,19 +13,23 @@ import (
 
 const (
 	DefaultExpectedStatus = 200
+	DefaultRetries        = 1
+	retryDelay            = 2 * time.Second
 )
 
 type EndpointCheckerSentinel struct {
-	url            string
-	expectedStatus int
-	expectedBody   string
-	client         *http.Client
+	url     string
+	checks  responseChecks
+	request httpRequestConfig
+	retries int
+	client  *http.Client
 }
 
 func NewEndpointCheckerSentinel() sentinel.Sentinel {
 	return &EndpointCheckerSentinel{
+		retries: DefaultRetries,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultHTTPTimeout,
 		},
 	}
 }
@@ -36,37 +40,56 @@ func (e *EndpointCheckerSentinel) Configure(config map[string]interface{}) error
 	} else {
 		e.url = url.(string)
 	}
-	if expectedStatus, ok := config["expected_status"]; ok {
-		if status, ok := expectedStatus.(float64); ok {
-			e.expectedStatus = int(status)
-		} else if status, ok := expectedStatus.(int); ok {
-			e.expectedStatus = status
-		} else {
-			return fmt.Errorf("expected_status must be a number")
-		}
-	} else {
-		e.expectedStatus = DefaultExpectedStatus
+	var err error
+	e.checks, err = parseResponseChecks(config)
+	if err != nil {
+		return err
+	}
+	e.request, err = parseHTTPRequestConfig(config)
+	if err != nil {
+		return err
+	}
+	retries, err := getInt64(config, "retries", DefaultRetries)
+	if err != nil {
+		return err
 	}
-	if expectedBody, ok := config["expected_body"]; ok {
-		e.expectedBody = expectedBody.(string)
+	if retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	e.retries = int(retries)
+	e.client, err = newHTTPClient(config)
+	if err != nil {
+		return err
 	}
 	return nil
 }
 
 func (e *EndpointCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
-	const maxRetries = 2
+	attempts := e.retries + 1
 	var response *http.Response
 	var err error
 	var responseTime time.Duration
 
-	for range maxRetries {
+	for attempt := range attempts {
+		var req *http.Request
+		req, err = e.request.newRequest(ctx, e.url)
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("failed to build request: %v", err),
+			}, nil
+		}
 		startTime := time.Now()
-		response, err = e.client.Get(e.url)
+		response, err = e.client.Do(req)
 		if err == nil {
 			responseTime = time.Since(startTime)
 			break
 		}
-		time.Sleep(2 * time.Second)
+		if attempt < attempts-1 {
+			time.Sleep(retryDelay)
+		}
 	}
 
 	if err != nil {
@@ -74,22 +97,14 @@ func (e *EndpointCheckerSentinel) Check(ctx context.Context, alarmID string) (si
 			AlarmID:   alarmID,
 			Status:    signal.StatusUnhealthy,
 			Timestamp: time.Now(),
-			Message:   fmt.Sprintf("failed after %d attempts: %v", maxRetries, err),
+			Message:   fmt.Sprintf("failed after %d attempts: %v", attempts, err),
 		}, nil
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != e.expectedStatus {
-		return signal.Signal{
-			AlarmID:   alarmID,
-			Status:    signal.StatusUnhealthy,
-			Timestamp: time.Now(),
-			Message:   fmt.Sprintf("expected status %d, got %d", e.expectedStatus, response.StatusCode),
-		}, nil
-	}
-
-	if e.expectedBody != "" {
-		body, err := io.ReadAll(response.Body)
+	var body []byte
+	if e.checks.needsBody() {
+		body, err = io.ReadAll(response.Body)
 		if err != nil {
 			return signal.Signal{
 				AlarmID:   alarmID,
@@ -98,15 +113,20 @@ func (e *EndpointCheckerSentinel) Check(ctx context.Context, alarmID string) (si
 				Message:   fmt.Sprintf("error reading body: %v", err),
 			}, nil
 		}
+	}
 
-		if string(body) != e.expectedBody {
-			return signal.Signal{
-				AlarmID:   alarmID,
-				Status:    signal.StatusUnhealthy,
-				Timestamp: time.Now(),
-				Message:   fmt.Sprintf("expected body %s, got %s", e.expectedBody, string(body)),
-			}, nil
-		}
+	metrics := map[string]float64{
+		"latency_ms":  float64(responseTime.Milliseconds()),
+		"status_code": float64(response.StatusCode),
+	}
+	if err := e.checks.evaluate(response, body, responseTime); err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+			Metrics:   metrics,
+		}, nil
 	}
 
 	return signal.Signal{
@@ -114,5 +134,6 @@ func (e *EndpointCheckerSentinel) Check(ctx context.Context, alarmID string) (si
 		Status:    signal.StatusHealthy,
 		Timestamp: time.Now(),
 		Message:   fmt.Sprintf("responded status %d in %vms", response.StatusCode, responseTime.Milliseconds()),
+		Metrics:   metrics,
 	}, nil
 }
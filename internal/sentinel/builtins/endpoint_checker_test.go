@@ -0,0 +1,370 @@
+package builtins
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration with assertions",
+			config: map[string]any{
+				"url":               "https://example.com/health",
+				"body_regex":        `"status":\s*"ok"`,
+				"max_response_time": "500ms",
+				"json_assertions": []any{
+					map[string]any{"path": "status", "equals": "ok"},
+					map[string]any{"path": "queue.depth", "lt": 100},
+				},
+				"header_assertions": []any{
+					map[string]any{"name": "Content-Type", "contains": "application/json"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing url",
+			config:      map[string]any{"expected_status": 200},
+			expectError: true,
+		},
+		{
+			name:        "invalid body regex",
+			config:      map[string]any{"url": "https://example.com", "body_regex": "(unclosed"},
+			expectError: true,
+		},
+		{
+			name: "json assertion without path",
+			config: map[string]any{
+				"url":             "https://example.com",
+				"json_assertions": []any{map[string]any{"equals": "ok"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "non numeric comparison value",
+			config: map[string]any{
+				"url":             "https://example.com",
+				"json_assertions": []any{map[string]any{"path": "count", "gt": "many"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "basic auth and bearer token",
+			config: map[string]any{
+				"url":          "https://example.com",
+				"basic_auth":   map[string]any{"username": "monitor", "password": "secret"},
+				"bearer_token": "abc123",
+			},
+			expectError: true,
+		},
+		{
+			name:        "client cert without key",
+			config:      map[string]any{"url": "https://example.com", "client_cert_file": "/etc/mirante/client.pem"},
+			expectError: true,
+		},
+		{
+			name:        "negative retries",
+			config:      map[string]any{"url": "https://example.com", "retries": -1},
+			expectError: true,
+		},
+		{
+			name:        "invalid max_response_time",
+			config:      map[string]any{"url": "https://example.com", "max_response_time": "fast"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEndpointCheckerSentinel()
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEndpointCheckerSentinel_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"ok","db":"up","timestamp":"2025-01-01T00:00:00Z","queue":{"depth":42},"nodes":[{"name":"a"},{"name":"b"}]}`))
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		config         map[string]any
+		expectedStatus signal.Status
+	}{
+		{
+			name:           "healthy - status only",
+			config:         map[string]any{"url": server.URL + "/health"},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - unexpected status",
+			config:         map[string]any{"url": server.URL + "/missing"},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name: "healthy - json assertions",
+			config: map[string]any{
+				"url": server.URL + "/health",
+				"json_assertions": []any{
+					map[string]any{"path": "status", "equals": "ok"},
+					map[string]any{"path": "db", "contains": "up"},
+					map[string]any{"path": "queue.depth", "gte": 10, "lt": 100},
+					map[string]any{"path": "nodes.#", "equals": 2},
+					map[string]any{"path": "nodes.1.name", "regex": "^b$"},
+					map[string]any{"path": "error", "exists": false},
+				},
+			},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name: "unhealthy - json field mismatch",
+			config: map[string]any{
+				"url":             server.URL + "/health",
+				"json_assertions": []any{map[string]any{"path": "db", "equals": "down"}},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name: "unhealthy - numeric threshold exceeded",
+			config: map[string]any{
+				"url":             server.URL + "/health",
+				"json_assertions": []any{map[string]any{"path": "queue.depth", "lt": 10}},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name: "unhealthy - json field missing",
+			config: map[string]any{
+				"url":             server.URL + "/health",
+				"json_assertions": []any{map[string]any{"path": "cache.status", "equals": "up"}},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "healthy - body regex",
+			config:         map[string]any{"url": server.URL + "/health", "body_regex": `"db":"up"`},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - body regex mismatch",
+			config:         map[string]any{"url": server.URL + "/health", "body_regex": `"db":"down"`},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name: "healthy - header assertion",
+			config: map[string]any{
+				"url":               server.URL + "/health",
+				"header_assertions": []any{map[string]any{"name": "content-type", "equals": "application/json"}},
+			},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name: "unhealthy - missing header",
+			config: map[string]any{
+				"url":               server.URL + "/health",
+				"header_assertions": []any{map[string]any{"name": "X-Request-Id", "exists": true}},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "unhealthy - response time exceeded",
+			config:         map[string]any{"url": server.URL + "/slow", "max_response_time": "10ms"},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "healthy - exact body",
+			config:         map[string]any{"url": server.URL + "/slow", "expected_body": "ok"},
+			expectedStatus: signal.StatusHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEndpointCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.NotEmpty(t, sig.Message)
+		})
+	}
+}
+
+func TestEndpointCheckerSentinel_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := NewEndpointCheckerSentinel()
+	require.NoError(t, s.Configure(map[string]any{"url": server.URL}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+	assert.Equal(t, float64(http.StatusServiceUnavailable), sig.Metrics["status_code"])
+	assert.GreaterOrEqual(t, sig.Metrics["latency_ms"], float64(20))
+}
+
+func TestEndpointCheckerSentinel_CheckRequestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || r.Header.Get("X-Tenant") != "acme" || string(body) != `{"limit":1}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.Header.Get("Authorization") != "Bearer abc123" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "/basic":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "monitor" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/redirect":
+			http.Redirect(w, r, "/basic", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		config         map[string]any
+		expectedStatus signal.Status
+	}{
+		{
+			name: "healthy - post with headers, body and bearer token",
+			config: map[string]any{
+				"url":             server.URL + "/orders",
+				"method":          "post",
+				"headers":         map[string]any{"X-Tenant": "acme"},
+				"body":            `{"limit":1}`,
+				"bearer_token":    "abc123",
+				"expected_status": 201,
+			},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name: "unhealthy - missing bearer token",
+			config: map[string]any{
+				"url":             server.URL + "/orders",
+				"method":          "POST",
+				"headers":         map[string]any{"X-Tenant": "acme"},
+				"body":            `{"limit":1}`,
+				"expected_status": 201,
+			},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name: "healthy - basic auth",
+			config: map[string]any{
+				"url":        server.URL + "/basic",
+				"basic_auth": map[string]any{"username": "monitor", "password": "secret"},
+			},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name: "healthy - redirect not followed",
+			config: map[string]any{
+				"url":              server.URL + "/redirect",
+				"follow_redirects": false,
+				"expected_status":  302,
+			},
+			expectedStatus: signal.StatusHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEndpointCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+		})
+	}
+}
+
+func TestEndpointCheckerSentinel_CheckTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pemEncodeCertificate(server.Certificate().Raw), 0644))
+
+	tests := []struct {
+		name           string
+		config         map[string]any
+		expectedStatus signal.Status
+	}{
+		{
+			name:           "unhealthy - untrusted certificate",
+			config:         map[string]any{"url": server.URL, "retries": 0, "timeout": "2s"},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "healthy - custom CA",
+			config:         map[string]any{"url": server.URL, "ca_file": caFile},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - insecure skip verify",
+			config:         map[string]any{"url": server.URL, "insecure_skip_verify": true},
+			expectedStatus: signal.StatusHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEndpointCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+		})
+	}
+}
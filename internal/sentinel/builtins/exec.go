@@ -0,0 +1,293 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const DefaultExecTimeout = 30 * time.Second
+
+var execOutputFormats = []string{"text", "json"}
+
+var execExitCodeConventions = []string{"default", "nagios"}
+
+var (
+	execAllowlistMu sync.RWMutex
+	execAllowlist   []string
+)
+
+// SetExecAllowlist sets the executables the exec sentinel may run. Entries are
+// absolute paths to executables or to directories whose executables are all
+// allowed. The exec sentinel is disabled while the list is empty, since alarm
+// configs can be created through the API.
+func SetExecAllowlist(paths []string) {
+	execAllowlistMu.Lock()
+	defer execAllowlistMu.Unlock()
+	execAllowlist = nil
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path != "" {
+			execAllowlist = append(execAllowlist, resolveExecPath(path))
+		}
+	}
+}
+
+// allowedExecCommand resolves command to the absolute path that will be run
+// and checks it against the allowlist.
+func allowedExecCommand(command string) (string, error) {
+	execAllowlistMu.RLock()
+	allowlist := execAllowlist
+	execAllowlistMu.RUnlock()
+	if len(allowlist) == 0 {
+		return "", fmt.Errorf("exec sentinel is disabled on this worker, set EXEC_SENTINEL_ALLOWLIST to enable it")
+	}
+
+	path := command
+	if !strings.ContainsRune(command, filepath.Separator) {
+		var err error
+		if path, err = exec.LookPath(command); err != nil {
+			return "", fmt.Errorf("command %s not found in PATH", command)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("command %s must be an absolute path", command)
+	}
+	path = resolveExecPath(path)
+	for _, allowed := range allowlist {
+		if rel, err := filepath.Rel(allowed, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("command %s is not in EXEC_SENTINEL_ALLOWLIST", command)
+}
+
+// resolveExecPath cleans path and resolves its symlinks, so neither `..` nor a
+// link can point outside an allowed directory. A missing file is resolved
+// through its directory and fails when it is run.
+func resolveExecPath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	} else if errors.Is(err, fs.ErrNotExist) {
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+			return filepath.Join(dir, filepath.Base(path))
+		}
+	}
+	return path
+}
+
+// ExecSentinel runs a local executable. Exit code 0 is healthy, 1 is unhealthy
+// and anything else is unknown, unless exit codes follow the Nagios plugin
+// convention. With the json output format the executable may print
+// {"status": ..., "message": ..., "metrics": {...}, "labels": {...}} instead.
+type ExecSentinel struct {
+	command    string
+	args       []string
+	env        []string
+	workingDir string
+	timeout    time.Duration
+	output     string
+	exitCodes  string
+}
+
+type execJSONOutput struct {
+	Status  signal.Status      `json:"status"`
+	Message string             `json:"message"`
+	Metrics map[string]float64 `json:"metrics"`
+	Labels  map[string]string  `json:"labels"`
+}
+
+func NewExecSentinel() sentinel.Sentinel {
+	return &ExecSentinel{}
+}
+
+func (s *ExecSentinel) Configure(config map[string]any) error {
+	var err error
+	if s.command, err = getString(config, "command", ""); err != nil {
+		return err
+	}
+	if s.command == "" {
+		return fmt.Errorf("missing required field: command")
+	}
+	if s.command, err = allowedExecCommand(s.command); err != nil {
+		return err
+	}
+	if args, ok := config["args"]; ok {
+		list, ok := args.([]any)
+		if !ok {
+			return fmt.Errorf("args must be a list")
+		}
+		for _, arg := range list {
+			s.args = append(s.args, stringifyValue(arg))
+		}
+	}
+
+	inheritEnv, err := getBool(config, "inherit_env", false)
+	if err != nil {
+		return err
+	}
+	if inheritEnv {
+		s.env = os.Environ()
+	} else {
+		s.env = []string{"PATH=" + os.Getenv("PATH")}
+	}
+	if env, ok := config["env"]; ok {
+		envMap, ok := env.(map[string]any)
+		if !ok {
+			return fmt.Errorf("env must be a map")
+		}
+		names := make([]string, 0, len(envMap))
+		for name := range envMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			s.env = append(s.env, name+"="+stringifyValue(envMap[name]))
+		}
+	}
+
+	if s.workingDir, err = getString(config, "working_dir", ""); err != nil {
+		return err
+	}
+	if s.timeout, err = getDuration(config, "timeout", DefaultExecTimeout); err != nil {
+		return err
+	}
+	if s.output, err = getString(config, "output", "text"); err != nil {
+		return err
+	}
+	if !slices.Contains(execOutputFormats, s.output) {
+		return fmt.Errorf("unsupported output %s, expected one of %v", s.output, execOutputFormats)
+	}
+	if s.exitCodes, err = getString(config, "exit_codes", "default"); err != nil {
+		return err
+	}
+	if !slices.Contains(execExitCodeConventions, s.exitCodes) {
+		return fmt.Errorf("unsupported exit_codes %s, expected one of %v", s.exitCodes, execExitCodeConventions)
+	}
+	return nil
+}
+
+func (s *ExecSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.command, s.args...)
+	cmd.Env = s.env
+	cmd.Dir = s.workingDir
+	// Don't wait forever on pipes held open by grandchildren after a timeout.
+	cmd.WaitDelay = time.Second
+	stdout := &limitedBuffer{limit: maxCommandOutputSize}
+	stderr := &limitedBuffer{limit: maxCommandOutputSize}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("command timed out after %v", s.timeout),
+		}, nil
+	}
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to run command: %v", err),
+		}, nil
+	}
+
+	status := execExitStatus(exitCode, s.exitCodes)
+	message := firstLine(stdout.String())
+	var metrics map[string]float64
+	var labels map[string]string
+	if s.output == "json" {
+		var output execJSONOutput
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("failed to parse command output as JSON: %v", err),
+			}, nil
+		}
+		if output.Status != "" {
+			if !output.Status.IsValid() {
+				return signal.Signal{
+					AlarmID:   alarmID,
+					Status:    signal.StatusUnknown,
+					Timestamp: time.Now(),
+					Message:   fmt.Sprintf("command reported unsupported status %s", output.Status),
+				}, nil
+			}
+			status = output.Status
+		}
+		message = output.Message
+		metrics, labels = output.Metrics, output.Labels
+		if len(output.Metrics) > 0 {
+			message = strings.TrimSpace(message + " " + formatExecMetrics(output.Metrics))
+		}
+	}
+	if message == "" {
+		message = fmt.Sprintf("command exited with status %d", exitCode)
+		if line := firstLine(stderr.String()); line != "" {
+			message += ": " + line
+		}
+	}
+
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    status,
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+		Labels:    labels,
+	}, nil
+}
+
+// execExitStatus maps an exit code to a status. Nagios plugins exit with 0
+// (OK), 1 (WARNING), 2 (CRITICAL) or 3 (UNKNOWN).
+func execExitStatus(exitCode int, convention string) signal.Status {
+	switch {
+	case exitCode == 0:
+		return signal.StatusHealthy
+	case exitCode == 1 && convention == "nagios":
+		return signal.StatusWarning
+	case exitCode == 1, exitCode == 2 && convention == "nagios":
+		return signal.StatusUnhealthy
+	default:
+		return signal.StatusUnknown
+	}
+}
+
+func formatExecMetrics(metrics map[string]float64) string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, formatNumber(metrics[name])))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
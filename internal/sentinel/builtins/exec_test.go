@@ -0,0 +1,318 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowExec sets the exec allowlist for the duration of the test.
+func allowExec(t *testing.T, paths ...string) {
+	SetExecAllowlist(paths)
+	t.Cleanup(func() { SetExecAllowlist(nil) })
+}
+
+// writeScript writes an executable script to a directory on the allowlist.
+func writeScript(t *testing.T, body string) string {
+	dir := t.TempDir()
+	allowExec(t, dir)
+	path := filepath.Join(dir, "check.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755))
+	return path
+}
+
+func TestExecSentinel_Configure(t *testing.T) {
+	allowExec(t, "/usr/lib/nagios/plugins")
+
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"command": "/usr/lib/nagios/plugins/check_disk",
+				"args":    []any{"-w", "20%", "-c", 10},
+				"env":     map[string]any{"LANG": "C"},
+				"timeout": "5s",
+				"output":  "json",
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing command",
+			config:      map[string]any{},
+			expectError: true,
+		},
+		{
+			name:        "args not a list",
+			config:      map[string]any{"command": "/usr/lib/nagios/plugins/check_disk", "args": "-v"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported output",
+			config:      map[string]any{"command": "/usr/lib/nagios/plugins/check_disk", "output": "xml"},
+			expectError: true,
+		},
+		{
+			name:        "nagios exit codes",
+			config:      map[string]any{"command": "/usr/lib/nagios/plugins/check_disk", "exit_codes": "nagios"},
+			expectError: false,
+		},
+		{
+			name:        "unsupported exit codes",
+			config:      map[string]any{"command": "/usr/lib/nagios/plugins/check_disk", "exit_codes": "lsb"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewExecSentinel()
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExecSentinel_Check(t *testing.T) {
+	t.Setenv("MIRANTE_TEST_SECRET", "leaked")
+
+	tests := []struct {
+		name            string
+		script          string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+	}{
+		{
+			name:            "healthy - exit 0 uses first line of stdout",
+			script:          "echo 'OK - 12 jobs queued'\necho 'details'\n",
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "OK - 12 jobs queued",
+		},
+		{
+			name:            "unhealthy - exit 1",
+			script:          "echo 'CRITICAL - backup is 3 days old'\nexit 1\n",
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "CRITICAL - backup is 3 days old",
+		},
+		{
+			name:            "unknown - other exit codes",
+			script:          "echo 'cannot reach backup server' >&2\nexit 3\n",
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "command exited with status 3: cannot reach backup server",
+		},
+		{
+			name:            "nagios warning - exit 1",
+			script:          "echo 'WARNING - disk 85% full'\nexit 1\n",
+			config:          map[string]any{"exit_codes": "nagios"},
+			expectedStatus:  signal.StatusWarning,
+			expectedMessage: "WARNING - disk 85% full",
+		},
+		{
+			name:            "nagios critical - exit 2",
+			script:          "echo 'CRITICAL - disk 97% full'\nexit 2\n",
+			config:          map[string]any{"exit_codes": "nagios"},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "CRITICAL - disk 97% full",
+		},
+		{
+			name:            "args and env are passed",
+			script:          "echo \"$1 $2 $GREETING ${MIRANTE_TEST_SECRET:-unset}\"\n",
+			config:          map[string]any{"args": []any{"hello", 42}, "env": map[string]any{"GREETING": "hi"}},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "hello 42 hi unset",
+		},
+		{
+			name:            "inherited env",
+			script:          "echo \"$MIRANTE_TEST_SECRET\"\n",
+			config:          map[string]any{"inherit_env": true},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "leaked",
+		},
+		{
+			name:            "json output overrides status",
+			script:          "echo '{\"status\": \"unhealthy\", \"message\": \"replication lag\", \"metrics\": {\"lag_seconds\": 93.5, \"replicas\": 2}}'\n",
+			config:          map[string]any{"output": "json"},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "replication lag (lag_seconds=93.5, replicas=2)",
+		},
+		{
+			name:            "json output without status uses exit code",
+			script:          "echo '{\"message\": \"queue is stuck\"}'\nexit 1\n",
+			config:          map[string]any{"output": "json"},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "queue is stuck",
+		},
+		{
+			name:            "invalid json output",
+			script:          "echo 'OK'\n",
+			config:          map[string]any{"output": "json"},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "failed to parse command output as JSON: invalid character 'O' looking for beginning of value",
+		},
+		{
+			name:            "unsupported json status",
+			script:          "echo '{\"status\": \"degraded\"}'\n",
+			config:          map[string]any{"output": "json"},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "command reported unsupported status degraded",
+		},
+		{
+			name:            "timeout",
+			script:          "sleep 5\n",
+			config:          map[string]any{"timeout": "100ms"},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "command timed out after 100ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{"command": writeScript(t, tt.script)}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			s := NewExecSentinel()
+			require.NoError(t, s.Configure(config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+		})
+	}
+
+	t.Run("json metrics and labels", func(t *testing.T) {
+		s := NewExecSentinel()
+		require.NoError(t, s.Configure(map[string]any{
+			"command": writeScript(t, "echo '{\"metrics\": {\"lag_seconds\": 93.5}, \"labels\": {\"replica\": \"db-2\"}}'\n"),
+			"output":  "json",
+		}))
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusHealthy, sig.Status)
+		assert.Equal(t, map[string]float64{"lag_seconds": 93.5}, sig.Metrics)
+		assert.Equal(t, map[string]string{"replica": "db-2"}, sig.Labels)
+	})
+
+	t.Run("missing executable", func(t *testing.T) {
+		dir := t.TempDir()
+		allowExec(t, dir)
+		s := NewExecSentinel()
+		require.NoError(t, s.Configure(map[string]any{"command": filepath.Join(dir, "missing")}))
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusUnknown, sig.Status)
+		assert.Contains(t, sig.Message, "failed to run command")
+	})
+}
+
+func TestExecExitStatus(t *testing.T) {
+	tests := []struct {
+		exitCode int
+		expected signal.Status
+		nagios   signal.Status
+	}{
+		{exitCode: 0, expected: signal.StatusHealthy, nagios: signal.StatusHealthy},
+		{exitCode: 1, expected: signal.StatusUnhealthy, nagios: signal.StatusWarning},
+		{exitCode: 2, expected: signal.StatusUnknown, nagios: signal.StatusUnhealthy},
+		{exitCode: 3, expected: signal.StatusUnknown, nagios: signal.StatusUnknown},
+		{exitCode: 4, expected: signal.StatusUnknown, nagios: signal.StatusUnknown},
+		{exitCode: 126, expected: signal.StatusUnknown, nagios: signal.StatusUnknown},
+		{exitCode: 127, expected: signal.StatusUnknown, nagios: signal.StatusUnknown},
+		{exitCode: 255, expected: signal.StatusUnknown, nagios: signal.StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.exitCode), func(t *testing.T) {
+			assert.Equal(t, tt.expected, execExitStatus(tt.exitCode, "default"))
+			assert.Equal(t, tt.nagios, execExitStatus(tt.exitCode, "nagios"))
+		})
+	}
+}
+
+func TestExecSentinel_Allowlist(t *testing.T) {
+	script := writeScript(t, "echo ok\n")
+	dir := filepath.Dir(script)
+	outside := filepath.Join(t.TempDir(), "outside")
+	require.NoError(t, os.Symlink("/bin/sh", filepath.Join(dir, "sh")))
+
+	tests := []struct {
+		name          string
+		allowlist     []string
+		command       string
+		expectedError string
+	}{
+		{
+			name:          "disabled without an allowlist",
+			command:       script,
+			expectedError: "exec sentinel is disabled on this worker, set EXEC_SENTINEL_ALLOWLIST to enable it",
+		},
+		{
+			name:      "allowed directory",
+			allowlist: []string{dir},
+			command:   script,
+		},
+		{
+			name:      "allowed executable",
+			allowlist: []string{script},
+			command:   script,
+		},
+		{
+			name:          "outside the allowlist",
+			allowlist:     []string{dir},
+			command:       outside,
+			expectedError: "command " + outside + " is not in EXEC_SENTINEL_ALLOWLIST",
+		},
+		{
+			name:          "escapes with dot dot",
+			allowlist:     []string{dir},
+			command:       dir + "/../outside",
+			expectedError: "command " + dir + "/../outside is not in EXEC_SENTINEL_ALLOWLIST",
+		},
+		{
+			name:          "escapes with a symlink",
+			allowlist:     []string{dir},
+			command:       filepath.Join(dir, "sh"),
+			expectedError: "command " + filepath.Join(dir, "sh") + " is not in EXEC_SENTINEL_ALLOWLIST",
+		},
+		{
+			name:          "other executable than the allowed one",
+			allowlist:     []string{script},
+			command:       "sh",
+			expectedError: "command sh is not in EXEC_SENTINEL_ALLOWLIST",
+		},
+		{
+			name:          "relative path",
+			allowlist:     []string{dir},
+			command:       "./check.sh",
+			expectedError: "command ./check.sh must be an absolute path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowExec(t, tt.allowlist...)
+			err := NewExecSentinel().Configure(map[string]any{"command": tt.command})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
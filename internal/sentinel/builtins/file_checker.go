@@ -0,0 +1,277 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/pkg/sftp"
+)
+
+const (
+	DefaultFileCheckerTimeout = 30 * time.Second
+	maxFileContentSize        = 1 << 20
+)
+
+// fileSystem is the subset of file operations the file checker needs, served
+// from the local disk or over SFTP.
+type fileSystem interface {
+	Stat(name string) (fs.FileInfo, error)
+	Glob(pattern string) ([]string, error)
+	Open(name string) (io.ReadCloser, error)
+}
+
+type localFileSystem struct{}
+
+func (localFileSystem) Stat(name string) (fs.FileInfo, error)   { return os.Stat(name) }
+func (localFileSystem) Glob(pattern string) ([]string, error)   { return filepath.Glob(pattern) }
+func (localFileSystem) Open(name string) (io.ReadCloser, error) { return os.Open(name) }
+
+type sftpFileSystem struct {
+	client *sftp.Client
+}
+
+func (s sftpFileSystem) Stat(name string) (fs.FileInfo, error)   { return s.client.Stat(name) }
+func (s sftpFileSystem) Glob(pattern string) ([]string, error)   { return s.client.Glob(pattern) }
+func (s sftpFileSystem) Open(name string) (io.ReadCloser, error) { return s.client.Open(name) }
+
+type FileCheckerSentinel struct {
+	path         string
+	glob         string
+	exists       bool
+	allowMissing bool
+	age          *thresholds
+	size         *thresholds
+	count        *thresholds
+	contentMatch *regexp.Regexp
+	timeout      time.Duration
+	connection   *connections.TunnelConfig
+}
+
+func NewFileCheckerSentinel() sentinel.Sentinel {
+	return &FileCheckerSentinel{}
+}
+
+func (s *FileCheckerSentinel) Configure(config map[string]any) error {
+	var err error
+	if s.path, err = getString(config, "path", ""); err != nil {
+		return err
+	}
+	if s.glob, err = getString(config, "glob", ""); err != nil {
+		return err
+	}
+	if (s.path == "") == (s.glob == "") {
+		return fmt.Errorf("exactly one of path or glob is required")
+	}
+	if s.exists, err = getBool(config, "exists", true); err != nil {
+		return err
+	}
+	if s.allowMissing, err = getBool(config, "allow_missing", false); err != nil {
+		return err
+	}
+	if s.age, err = parseNestedThresholds(config, "age", "<=", true); err != nil {
+		return err
+	}
+	if s.size, err = parseNestedThresholds(config, "size", ">=", false); err != nil {
+		return err
+	}
+	if s.count, err = parseNestedThresholds(config, "count", ">=", false); err != nil {
+		return err
+	}
+	if s.count != nil && s.glob == "" {
+		return fmt.Errorf("count requires glob")
+	}
+	contentRegex, err := getString(config, "content_regex", "")
+	if err != nil {
+		return err
+	}
+	if contentRegex != "" {
+		if s.contentMatch, err = regexp.Compile(contentRegex); err != nil {
+			return fmt.Errorf("invalid `content_regex`: %v", err)
+		}
+	}
+	if !s.exists && (s.age != nil || s.size != nil || s.contentMatch != nil) {
+		return fmt.Errorf("age, size and content_regex can't be checked when the file must not exist")
+	}
+	if s.timeout, err = getDuration(config, "timeout", DefaultFileCheckerTimeout); err != nil {
+		return err
+	}
+
+	if connConfig, ok := config["connection"].(map[string]any); ok {
+		if s.connection, err = connections.NewTunnelConfig(connConfig); err != nil {
+			return fmt.Errorf("failed to create SSH connection config: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *FileCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	type result struct {
+		sig signal.Signal
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		sig, err := s.inspect()
+		done <- result{sig, err}
+	}()
+
+	var sig signal.Signal
+	select {
+	case <-ctx.Done():
+		sig = signal.Signal{Status: signal.StatusUnknown, Message: fmt.Sprintf("file check timed out after %v", s.timeout)}
+	case r := <-done:
+		sig = r.sig
+		if r.err != nil {
+			sig = signal.Signal{Status: signal.StatusUnknown, Message: r.err.Error()}
+		}
+	}
+	sig.AlarmID = alarmID
+	sig.Timestamp = time.Now()
+	return sig, nil
+}
+
+func (s *FileCheckerSentinel) inspect() (signal.Signal, error) {
+	if s.connection == nil {
+		return s.evaluate(localFileSystem{})
+	}
+	tunnel, err := connections.OpenTunnel(*s.connection)
+	if err != nil {
+		var hostKeyErr *connections.HostKeyError
+		if errors.As(err, &hostKeyErr) {
+			return signal.Signal{}, hostKeyErr
+		}
+		return signal.Signal{}, fmt.Errorf("failed to connect to %s: %v", s.connection.Host, err)
+	}
+	defer tunnel.Close()
+	client, err := tunnel.NewSFTPClient()
+	if err != nil {
+		return signal.Signal{}, fmt.Errorf("failed to open SFTP session: %v", err)
+	}
+	defer client.Close()
+	return s.evaluate(sftpFileSystem{client: client})
+}
+
+func (s *FileCheckerSentinel) evaluate(fsys fileSystem) (signal.Signal, error) {
+	worst := thresholdOK
+	var observations, failures []string
+	metrics := make(map[string]float64)
+	labels := make(map[string]string)
+	check := func(t *thresholds, value float64, description string) {
+		if t == nil {
+			return
+		}
+		level := t.evaluate(value)
+		if level != thresholdOK {
+			worst = max(worst, level)
+			failures = append(failures, fmt.Sprintf("%s: %s, %s", level, description, t.describe(level)))
+		}
+	}
+
+	path := s.path
+	var info fs.FileInfo
+	if s.glob != "" {
+		matches, err := fsys.Glob(s.glob)
+		if err != nil {
+			return signal.Signal{}, fmt.Errorf("failed to match %s: %v", s.glob, err)
+		}
+		existing := 0
+		for _, match := range matches {
+			matchInfo, err := fsys.Stat(match)
+			// A match may be rotated away between listing and stat.
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return signal.Signal{}, fmt.Errorf("failed to stat %s: %v", match, err)
+			}
+			existing++
+			if info == nil || matchInfo.ModTime().After(info.ModTime()) {
+				path, info = match, matchInfo
+			}
+		}
+		description := fmt.Sprintf("%d files match %s", existing, s.glob)
+		observations = append(observations, description)
+		metrics["count"] = float64(existing)
+		check(s.count, float64(existing), description)
+	} else {
+		var err error
+		info, err = fsys.Stat(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return signal.Signal{}, fmt.Errorf("failed to stat %s: %v", path, err)
+		}
+	}
+
+	if info == nil {
+		missing := fmt.Sprintf("%s does not exist", s.path)
+		if s.glob != "" {
+			missing = fmt.Sprintf("no file matches %s", s.glob)
+		}
+		if !s.exists || s.allowMissing {
+			observations = append(observations, missing)
+		} else {
+			worst = max(worst, thresholdCritical)
+			failures = append(failures, fmt.Sprintf("%s: %s", thresholdCritical, missing))
+		}
+	} else if !s.exists {
+		worst = max(worst, thresholdCritical)
+		failures = append(failures, fmt.Sprintf("%s: %s exists", thresholdCritical, path))
+	} else {
+		age := time.Since(info.ModTime())
+		metrics["age_seconds"] = age.Seconds()
+		metrics["size_bytes"] = float64(info.Size())
+		labels["path"] = path
+		ageDescription := fmt.Sprintf("%s is %v old", path, age.Round(time.Second))
+		sizeDescription := fmt.Sprintf("%s is %d bytes", path, info.Size())
+		observations = append(observations, fmt.Sprintf("%s is %v old and %d bytes", path, age.Round(time.Second), info.Size()))
+		check(s.age, age.Seconds(), ageDescription)
+		check(s.size, float64(info.Size()), sizeDescription)
+		if s.contentMatch != nil {
+			matched, err := s.matchContent(fsys, path, info)
+			if err != nil {
+				return signal.Signal{}, err
+			}
+			if !matched {
+				worst = max(worst, thresholdCritical)
+				failures = append(failures, fmt.Sprintf("%s: %s doesn't match %s", thresholdCritical, path, s.contentMatch.String()))
+			}
+		}
+	}
+
+	sig := signal.Signal{Status: worst.status(), Message: strings.Join(failures, "; "), Metrics: metrics, Labels: labels}
+	if worst == thresholdOK {
+		sig.Message = strings.Join(observations, ", ")
+	}
+	return sig, nil
+}
+
+// matchContent matches the content regex against the first
+// maxFileContentSize bytes of the file.
+func (s *FileCheckerSentinel) matchContent(fsys fileSystem, path string, info fs.FileInfo) (bool, error) {
+	if info.IsDir() {
+		return false, fmt.Errorf("%s is a directory, content_regex needs a file", path)
+	}
+	file, err := fsys.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxFileContentSize))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return s.contentMatch.Match(content), nil
+}
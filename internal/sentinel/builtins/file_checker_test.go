@@ -0,0 +1,227 @@
+package builtins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path string, content string, age time.Duration) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	modified := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+func TestFileCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"path":          "/var/backups/db.sql.gz",
+				"age":           map[string]any{"critical": "26h"},
+				"size":          map[string]any{"critical": 1048576},
+				"content_regex": "COMPLETED",
+				"connection":    map[string]any{"host": "db.example.com", "port": 22, "user": "monitor", "password": "secret"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "path and glob",
+			config:      map[string]any{"path": "/var/backups/db.sql.gz", "glob": "/var/backups/*.gz"},
+			expectError: true,
+		},
+		{
+			name:        "neither path nor glob",
+			config:      map[string]any{"age": map[string]any{"critical": "1h"}},
+			expectError: true,
+		},
+		{
+			name:        "count without glob",
+			config:      map[string]any{"path": "/var/backups", "count": map[string]any{"critical": 7}},
+			expectError: true,
+		},
+		{
+			name:        "age of a file that must not exist",
+			config:      map[string]any{"path": "/var/run/import.lock", "exists": false, "age": map[string]any{"critical": "1h"}},
+			expectError: true,
+		},
+		{
+			name:        "invalid content regex",
+			config:      map[string]any{"path": "/var/log/app.log", "content_regex": "("},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewFileCheckerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFileCheckerSentinel_Check(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "2025-05-31.sql"), "-- dump COMPLETED\n", 26*time.Hour)
+	writeTestFile(t, filepath.Join(dir, "2025-06-01.sql"), "-- dump started\n", 2*time.Hour)
+	writeTestFile(t, filepath.Join(dir, "import.lock"), "4242\n", 3*time.Hour)
+	connection, _ := startSSHCommandServer(t, nil)
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name:            "fresh file",
+			config:          map[string]any{"path": filepath.Join(dir, "2025-06-01.sql"), "age": map[string]any{"critical": "26h"}},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: filepath.Join(dir, "2025-06-01.sql") + " is 2h0m0s old and 16 bytes",
+			expectedMetrics: map[string]float64{"age_seconds": 7200, "size_bytes": 16},
+		},
+		{
+			name:            "missing file",
+			config:          map[string]any{"path": filepath.Join(dir, "missing.sql")},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "missing.sql") + " does not exist",
+			expectedMetrics: map[string]float64{},
+		},
+		{
+			name:            "size bounds",
+			config:          map[string]any{"path": filepath.Join(dir, "2025-06-01.sql"), "size": map[string]any{"operator": "between", "critical": []any{1024, 1048576}}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "2025-06-01.sql") + " is 16 bytes, expected between 1024 and 1048576",
+		},
+		{
+			name:            "content regex",
+			config:          map[string]any{"glob": filepath.Join(dir, "*.sql"), "content_regex": "COMPLETED"},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "2025-06-01.sql") + " doesn't match COMPLETED",
+		},
+		{
+			name: "glob count",
+			config: map[string]any{
+				"glob":  filepath.Join(dir, "*.sql"),
+				"count": map[string]any{"critical": 7},
+				"age":   map[string]any{"critical": "26h", "warning": "1h"},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+			expectedMessage: "critical: 2 files match " + filepath.Join(dir, "*.sql") + ", expected >= 7; " +
+				"warning: " + filepath.Join(dir, "2025-06-01.sql") + " is 2h0m0s old, expected <= 3600",
+			expectedMetrics: map[string]float64{"count": 2, "age_seconds": 7200, "size_bytes": 16},
+		},
+		{
+			name:            "no file matches glob",
+			config:          map[string]any{"glob": filepath.Join(dir, "*.gz")},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: no file matches " + filepath.Join(dir, "*.gz"),
+		},
+		{
+			name:            "lock file must not exist",
+			config:          map[string]any{"path": filepath.Join(dir, "import.lock"), "exists": false},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "import.lock") + " exists",
+		},
+		{
+			name:            "stale lock file",
+			config:          map[string]any{"path": filepath.Join(dir, "import.lock"), "allow_missing": true, "age": map[string]any{"critical": "1h"}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "import.lock") + " is 3h0m0s old, expected <= 3600",
+		},
+		{
+			name:            "missing lock file is allowed",
+			config:          map[string]any{"path": filepath.Join(dir, "export.lock"), "allow_missing": true, "age": map[string]any{"critical": "1h"}},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: filepath.Join(dir, "export.lock") + " does not exist",
+		},
+		{
+			name: "remote file over SSH",
+			config: map[string]any{
+				"glob":          filepath.Join(dir, "*.sql"),
+				"count":         map[string]any{"critical": 2},
+				"content_regex": "dump",
+				"connection":    connection,
+			},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "2 files match " + filepath.Join(dir, "*.sql") + ", " + filepath.Join(dir, "2025-06-01.sql") + " is 2h0m",
+			expectedMetrics: map[string]float64{"count": 2, "age_seconds": 7200, "size_bytes": 16},
+		},
+		{
+			name: "missing remote file",
+			config: map[string]any{
+				"path":       filepath.Join(dir, "missing.sql"),
+				"connection": connection,
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: " + filepath.Join(dir, "missing.sql") + " does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFileCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			// SFTP reports modification times in whole seconds.
+			assert.True(t, strings.HasPrefix(sig.Message, tt.expectedMessage), "unexpected message %q", sig.Message)
+			if tt.expectedMetrics != nil {
+				assert.Len(t, sig.Metrics, len(tt.expectedMetrics))
+				for name, value := range tt.expectedMetrics {
+					assert.InDelta(t, value, sig.Metrics[name], 5, name)
+				}
+			}
+			if _, ok := tt.expectedMetrics["size_bytes"]; ok {
+				assert.Equal(t, filepath.Join(dir, "2025-06-01.sql"), sig.Labels["path"])
+			}
+		})
+	}
+}
+
+// rotatingFileSystem lists a file that is gone by the time it is stat'ed, as
+// when a log is rotated during the check.
+type rotatingFileSystem struct {
+	localFileSystem
+	rotated string
+}
+
+func (r rotatingFileSystem) Glob(pattern string) ([]string, error) {
+	matches, err := r.localFileSystem.Glob(pattern)
+	return append(matches, r.rotated), err
+}
+
+func TestFileCheckerSentinel_GlobMatchRotatedAway(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "app.log"), "started\n", time.Minute)
+	s := NewFileCheckerSentinel().(*FileCheckerSentinel)
+	require.NoError(t, s.Configure(map[string]any{
+		"glob":  filepath.Join(dir, "*.log"),
+		"count": map[string]any{"critical": 1},
+		"age":   map[string]any{"critical": "10m"},
+	}))
+
+	sig, err := s.evaluate(rotatingFileSystem{rotated: filepath.Join(dir, "app.1.log")})
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusHealthy, sig.Status)
+	assert.True(t, strings.HasPrefix(sig.Message, "1 files match "+filepath.Join(dir, "*.log")+", "+filepath.Join(dir, "app.log")+" is 1m0s old"), "unexpected message %q", sig.Message)
+}
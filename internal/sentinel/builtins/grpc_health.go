@@ -0,0 +1,171 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const DefaultGRPCTimeout = 10 * time.Second
+
+type GRPCHealthSentinel struct {
+	address     string
+	service     string
+	timeout     time.Duration
+	metadata    metadata.MD
+	credentials credentials.TransportCredentials
+	tunnel      *connections.TunnelConfig
+}
+
+func NewGRPCHealthSentinel() sentinel.Sentinel {
+	return &GRPCHealthSentinel{}
+}
+
+func (s *GRPCHealthSentinel) Configure(config map[string]any) error {
+	var err error
+	if s.address, err = getString(config, "address", ""); err != nil {
+		return err
+	}
+	if s.address == "" {
+		return fmt.Errorf("missing required field: address")
+	}
+	if _, _, err := net.SplitHostPort(s.address); err != nil {
+		return fmt.Errorf("invalid address %s: %v", s.address, err)
+	}
+	if s.service, err = getString(config, "service", ""); err != nil {
+		return err
+	}
+	if s.timeout, err = getDuration(config, "timeout", DefaultGRPCTimeout); err != nil {
+		return err
+	}
+
+	s.metadata = metadata.MD{}
+	if raw, ok := config["metadata"]; ok {
+		entries, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("metadata must be a map")
+		}
+		for name, value := range entries {
+			s.metadata.Append(name, stringifyValue(value))
+		}
+	}
+
+	useTLS, err := getBool(config, "tls", false)
+	if err != nil {
+		return err
+	}
+	s.credentials = insecure.NewCredentials()
+	if useTLS {
+		tlsConfig, err := newTLSClientConfig(config)
+		if err != nil {
+			return err
+		}
+		if tlsConfig.ServerName, err = getString(config, "server_name", ""); err != nil {
+			return err
+		}
+		s.credentials = credentials.NewTLS(tlsConfig)
+	}
+
+	if tunnelConfig, ok := config["tunnel"].(map[string]any); ok {
+		if s.tunnel, err = connections.NewTunnelConfig(tunnelConfig); err != nil {
+			return fmt.Errorf("failed to create tunnel config: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *GRPCHealthSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	options := []grpc.DialOption{grpc.WithTransportCredentials(s.credentials)}
+	if s.tunnel != nil && s.tunnel.Host != "" {
+		tunnel, err := connections.OpenTunnel(*s.tunnel)
+		if err != nil {
+			message := fmt.Sprintf("failed to connect to %s: %v", s.tunnel.Host, err)
+			var hostKeyErr *connections.HostKeyError
+			if errors.As(err, &hostKeyErr) {
+				message = hostKeyErr.Error()
+			}
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   message,
+			}, nil
+		}
+		defer tunnel.Close()
+		options = append(options, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return tunnel.DialContext(ctx, "tcp", addr)
+		}))
+	}
+
+	conn, err := grpc.NewClient("passthrough:///"+s.address, options...)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to create gRPC client: %v", err),
+		}, nil
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, s.metadata)
+
+	target := s.address
+	if s.service != "" {
+		target = fmt.Sprintf("%s on %s", s.service, s.address)
+	}
+	startTime := time.Now()
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: s.service})
+	responseTime := time.Since(startTime)
+	if err != nil {
+		rpcStatus := status.Convert(err)
+		sigStatus := signal.StatusUnhealthy
+		message := fmt.Sprintf("health check of %s failed: %s: %s", target, rpcStatus.Code(), rpcStatus.Message())
+		switch rpcStatus.Code() {
+		case codes.DeadlineExceeded:
+			message = fmt.Sprintf("health check of %s timed out after %v", target, s.timeout)
+		case codes.NotFound:
+			message = fmt.Sprintf("service %s is not registered on %s", s.service, s.address)
+		case codes.Unimplemented:
+			// The server is up but doesn't tell whether it's healthy.
+			sigStatus = signal.StatusUnknown
+			message = fmt.Sprintf("%s doesn't implement grpc.health.v1.Health", s.address)
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    sigStatus,
+			Timestamp: time.Now(),
+			Message:   message,
+		}, nil
+	}
+
+	if response.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("%s is %s", target, response.GetStatus()),
+		}, nil
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusHealthy,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("%s is SERVING, responded in %vms", target, responseTime.Milliseconds()),
+	}, nil
+}
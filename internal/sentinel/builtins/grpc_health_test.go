@@ -0,0 +1,209 @@
+package builtins
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startGRPCHealthServer serves the health service on a local port and rejects
+// calls that don't carry the expected authorization metadata.
+func startGRPCHealthServer(t *testing.T, options ...grpc.ServerOption) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	requireToken := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("authorization"); len(values) != 1 || values[0] != "Bearer secret" {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return handler(ctx, req)
+	}
+	server := grpc.NewServer(append(options, grpc.UnaryInterceptor(requireToken))...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders.v1.Orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("payments.v1.Payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestGRPCHealthSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"address":  "orders.internal:50051",
+				"service":  "orders.v1.Orders",
+				"timeout":  "5s",
+				"tls":      true,
+				"metadata": map[string]any{"authorization": "Bearer secret"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing address",
+			config:      map[string]any{"service": "orders.v1.Orders"},
+			expectError: true,
+		},
+		{
+			name:        "address without port",
+			config:      map[string]any{"address": "orders.internal"},
+			expectError: true,
+		},
+		{
+			name:        "invalid metadata",
+			config:      map[string]any{"address": "orders.internal:50051", "metadata": "authorization"},
+			expectError: true,
+		},
+		{
+			name:        "missing client key",
+			config:      map[string]any{"address": "orders.internal:50051", "tls": true, "client_cert_file": "client.pem"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGRPCHealthSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGRPCHealthSentinel_Check(t *testing.T) {
+	address := startGRPCHealthServer(t)
+	token := map[string]any{"authorization": "Bearer secret"}
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+	}{
+		{
+			name:            "serving service",
+			config:          map[string]any{"address": address, "service": "orders.v1.Orders", "metadata": token},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "orders.v1.Orders on " + address + " is SERVING",
+		},
+		{
+			name:            "overall server health",
+			config:          map[string]any{"address": address, "metadata": token},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: address + " is SERVING",
+		},
+		{
+			name:            "not serving service",
+			config:          map[string]any{"address": address, "service": "payments.v1.Payments", "metadata": token},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "payments.v1.Payments on " + address + " is NOT_SERVING",
+		},
+		{
+			name:            "unknown service",
+			config:          map[string]any{"address": address, "service": "users.v1.Users", "metadata": token},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "service users.v1.Users is not registered on " + address,
+		},
+		{
+			name:            "missing metadata",
+			config:          map[string]any{"address": address, "service": "orders.v1.Orders"},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "health check of orders.v1.Orders on " + address + " failed: Unauthenticated: invalid token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewGRPCHealthSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Contains(t, sig.Message, tt.expectedMessage)
+		})
+	}
+}
+
+func TestGRPCHealthSentinel_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	s := NewGRPCHealthSentinel()
+	require.NoError(t, s.Configure(map[string]any{"address": address}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+	assert.Contains(t, sig.Message, "failed: Unavailable")
+}
+
+func TestGRPCHealthSentinel_TLS(t *testing.T) {
+	// Borrow the httptest certificate, which is valid for 127.0.0.1.
+	tlsServer := httptest.NewTLSServer(nil)
+	certificate := tlsServer.TLS.Certificates[0]
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pemEncodeCertificate(tlsServer.Certificate().Raw), 0o600))
+	tlsServer.Close()
+
+	address := startGRPCHealthServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{certificate}})))
+	token := map[string]any{"authorization": "Bearer secret"}
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		s := NewGRPCHealthSentinel()
+		require.NoError(t, s.Configure(map[string]any{
+			"address":  address,
+			"service":  "orders.v1.Orders",
+			"tls":      true,
+			"ca_file":  caFile,
+			"metadata": token,
+		}))
+
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusHealthy, sig.Status)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		s := NewGRPCHealthSentinel()
+		require.NoError(t, s.Configure(map[string]any{
+			"address":  address,
+			"service":  "orders.v1.Orders",
+			"tls":      true,
+			"metadata": token,
+		}))
+
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+		assert.Contains(t, sig.Message, "Unavailable")
+	})
+}
@@ -0,0 +1,172 @@
+package builtins
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultHTTPTimeout = 30 * time.Second
+)
+
+// newHTTPClient builds a client from the `timeout`, `follow_redirects`,
+// `ca_file`, `insecure_skip_verify`, `client_cert_file` and `client_key_file`
+// config fields shared by the HTTP based sentinels.
+func newHTTPClient(config map[string]any) (*http.Client, error) {
+	timeout, err := getDuration(config, "timeout", DefaultHTTPTimeout)
+	if err != nil {
+		return nil, err
+	}
+	followRedirects, err := getBool(config, "follow_redirects", true)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+	if !followRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client, nil
+}
+
+func newTLSClientConfig(config map[string]any) (*tls.Config, error) {
+	insecureSkipVerify, err := getBool(config, "insecure_skip_verify", false)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	caFile, err := getString(config, "ca_file", "")
+	if err != nil {
+		return nil, err
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca_file %s", caFile)
+		}
+	}
+
+	certFile, err := getString(config, "client_cert_file", "")
+	if err != nil {
+		return nil, err
+	}
+	keyFile, err := getString(config, "client_key_file", "")
+	if err != nil {
+		return nil, err
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("client_cert_file and client_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+type httpRequestConfig struct {
+	method      string
+	headers     map[string]string
+	body        string
+	username    string
+	password    string
+	bearerToken string
+}
+
+func parseHTTPRequestConfig(config map[string]any) (httpRequestConfig, error) {
+	var r httpRequestConfig
+	method, err := getString(config, "method", http.MethodGet)
+	if err != nil {
+		return r, err
+	}
+	r.method = strings.ToUpper(method)
+
+	r.headers = make(map[string]string)
+	if headers, ok := config["headers"]; ok {
+		headerMap, ok := headers.(map[string]any)
+		if !ok {
+			return r, fmt.Errorf("headers must be a map")
+		}
+		for name, value := range headerMap {
+			r.headers[name] = stringifyValue(value)
+		}
+	}
+
+	if r.body, err = getString(config, "body", ""); err != nil {
+		return r, err
+	}
+
+	if basicAuth, ok := config["basic_auth"]; ok {
+		basicAuthConfig, ok := basicAuth.(map[string]any)
+		if !ok {
+			return r, fmt.Errorf("basic_auth must be a map")
+		}
+		if r.username, err = getString(basicAuthConfig, "username", ""); err != nil {
+			return r, err
+		}
+		if r.password, err = getString(basicAuthConfig, "password", ""); err != nil {
+			return r, err
+		}
+		if r.username == "" {
+			return r, fmt.Errorf("missing required field: basic_auth.username")
+		}
+	}
+	if r.bearerToken, err = getString(config, "bearer_token", ""); err != nil {
+		return r, err
+	}
+	if r.username != "" && r.bearerToken != "" {
+		return r, fmt.Errorf("basic_auth and bearer_token cannot both be set")
+	}
+	return r, nil
+}
+
+func (r httpRequestConfig) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	var body io.Reader
+	if r.body != "" {
+		body = strings.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	if r.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.bearerToken)
+	}
+	return req, nil
+}
@@ -0,0 +1,333 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+var httpFlowExtractionSources = []string{"json", "header", "cookie", "regex"}
+
+type httpFlowExtraction struct {
+	variable string
+	source   string
+	expr     string
+	regex    *regexp.Regexp
+}
+
+type httpFlowStep struct {
+	name    string
+	url     string
+	request httpRequestConfig
+	checks  responseChecks
+	extract []httpFlowExtraction
+}
+
+type HTTPFlowSentinel struct {
+	steps     []httpFlowStep
+	variables map[string]string
+	client    *http.Client
+}
+
+func NewHTTPFlowSentinel() sentinel.Sentinel {
+	return &HTTPFlowSentinel{}
+}
+
+func (s *HTTPFlowSentinel) Configure(config map[string]any) error {
+	rawSteps, ok := config["steps"]
+	if !ok {
+		return fmt.Errorf("missing required field: steps")
+	}
+	stepConfigs, ok := rawSteps.([]any)
+	if !ok || len(stepConfigs) == 0 {
+		return fmt.Errorf("steps must be a non-empty list")
+	}
+
+	s.variables = make(map[string]string)
+	if variables, ok := config["variables"]; ok {
+		variableMap, ok := variables.(map[string]any)
+		if !ok {
+			return fmt.Errorf("variables must be a map")
+		}
+		for name, value := range variableMap {
+			s.variables[name] = stringifyValue(value)
+		}
+	}
+
+	s.steps = make([]httpFlowStep, 0, len(stepConfigs))
+	for i, rawStep := range stepConfigs {
+		stepConfig, ok := rawStep.(map[string]any)
+		if !ok {
+			return fmt.Errorf("steps[%d] must be a map", i)
+		}
+		step, err := parseHTTPFlowStep(stepConfig, i)
+		if err != nil {
+			return fmt.Errorf("steps[%d]: %v", i, err)
+		}
+		s.steps = append(s.steps, step)
+	}
+
+	var err error
+	s.client, err = newHTTPClient(config)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseHTTPFlowStep(config map[string]any, index int) (httpFlowStep, error) {
+	var step httpFlowStep
+	var err error
+	if step.name, err = getString(config, "name", fmt.Sprintf("step %d", index+1)); err != nil {
+		return step, err
+	}
+	if _, ok := config["url"]; !ok {
+		return step, fmt.Errorf("missing required field: url")
+	}
+	if step.url, err = getString(config, "url", ""); err != nil {
+		return step, err
+	}
+	if step.request, err = parseHTTPRequestConfig(config); err != nil {
+		return step, err
+	}
+	if step.checks, err = parseResponseChecks(config); err != nil {
+		return step, err
+	}
+	if _, err := parseFlowTemplate(step.url); err != nil {
+		return step, fmt.Errorf("invalid template in url: %v", err)
+	}
+	for field, value := range step.request.templates() {
+		if _, err := parseFlowTemplate(value); err != nil {
+			return step, fmt.Errorf("invalid template in %s: %v", field, err)
+		}
+	}
+
+	if extract, ok := config["extract"]; ok {
+		extractMap, ok := extract.(map[string]any)
+		if !ok {
+			return step, fmt.Errorf("extract must be a map")
+		}
+		for variable, rawExtraction := range extractMap {
+			extraction, err := parseHTTPFlowExtraction(variable, rawExtraction)
+			if err != nil {
+				return step, fmt.Errorf("extract.%s: %v", variable, err)
+			}
+			step.extract = append(step.extract, extraction)
+		}
+	}
+	return step, nil
+}
+
+func parseHTTPFlowExtraction(variable string, raw any) (httpFlowExtraction, error) {
+	extraction := httpFlowExtraction{variable: variable}
+	config, ok := raw.(map[string]any)
+	if !ok || len(config) != 1 {
+		return extraction, fmt.Errorf("must be a map with exactly one of %v", httpFlowExtractionSources)
+	}
+	for source, value := range config {
+		expr, ok := value.(string)
+		if !ok {
+			return extraction, fmt.Errorf("can't convert `%s` to string: %v", source, value)
+		}
+		extraction.source = source
+		extraction.expr = expr
+	}
+	switch extraction.source {
+	case "json", "header", "cookie":
+	case "regex":
+		var err error
+		if extraction.regex, err = regexp.Compile(extraction.expr); err != nil {
+			return extraction, fmt.Errorf("invalid regex: %v", err)
+		}
+	default:
+		return extraction, fmt.Errorf("unsupported source %s, expected one of %v", extraction.source, httpFlowExtractionSources)
+	}
+	return extraction, nil
+}
+
+func (r httpRequestConfig) templates() map[string]string {
+	fields := map[string]string{
+		"body":                r.body,
+		"bearer_token":        r.bearerToken,
+		"basic_auth.username": r.username,
+		"basic_auth.password": r.password,
+	}
+	for name, value := range r.headers {
+		fields["headers."+name] = value
+	}
+	return fields
+}
+
+func (r httpRequestConfig) rendered(variables map[string]string) (httpRequestConfig, error) {
+	out := r
+	out.headers = make(map[string]string, len(r.headers))
+	var err error
+	for name, value := range r.headers {
+		if out.headers[name], err = renderFlowTemplate(value, variables); err != nil {
+			return out, fmt.Errorf("header %s: %v", name, err)
+		}
+	}
+	if out.body, err = renderFlowTemplate(r.body, variables); err != nil {
+		return out, fmt.Errorf("body: %v", err)
+	}
+	if out.bearerToken, err = renderFlowTemplate(r.bearerToken, variables); err != nil {
+		return out, fmt.Errorf("bearer_token: %v", err)
+	}
+	if out.username, err = renderFlowTemplate(r.username, variables); err != nil {
+		return out, fmt.Errorf("basic_auth.username: %v", err)
+	}
+	if out.password, err = renderFlowTemplate(r.password, variables); err != nil {
+		return out, fmt.Errorf("basic_auth.password: %v", err)
+	}
+	return out, nil
+}
+
+func parseFlowTemplate(text string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Parse(text)
+}
+
+// renderFlowTemplate resolves `{{ .variable }}` references against the values
+// extracted by previous steps.
+func renderFlowTemplate(text string, variables map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := parseFlowTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, variables); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func (e httpFlowExtraction) apply(response *http.Response, body []byte) (string, error) {
+	switch e.source {
+	case "json":
+		var document any
+		if err := json.Unmarshal(body, &document); err != nil {
+			return "", fmt.Errorf("failed to parse body as JSON: %v", err)
+		}
+		value, found := lookupJSONPath(document, e.expr)
+		if !found {
+			return "", fmt.Errorf("JSON path %s not found", e.expr)
+		}
+		return stringifyValue(value), nil
+	case "header":
+		value := response.Header.Get(e.expr)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found", e.expr)
+		}
+		return value, nil
+	case "cookie":
+		for _, cookie := range response.Cookies() {
+			if cookie.Name == e.expr {
+				return cookie.Value, nil
+			}
+		}
+		return "", fmt.Errorf("cookie %s not found", e.expr)
+	case "regex":
+		match := e.regex.FindSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("body does not match %s", e.expr)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	}
+	return "", fmt.Errorf("unsupported source %s", e.source)
+}
+
+func (s *HTTPFlowSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to create cookie jar: %v", err),
+		}, nil
+	}
+	client := *s.client
+	client.Jar = jar
+
+	variables := make(map[string]string, len(s.variables))
+	for name, value := range s.variables {
+		variables[name] = value
+	}
+
+	timings := make([]string, 0, len(s.steps))
+	var total time.Duration
+	for i, step := range s.steps {
+		elapsed, err := s.runStep(ctx, &client, step, variables)
+		total += elapsed
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnhealthy,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("step %d (%s) failed after %vms: %v", i+1, step.name, elapsed.Milliseconds(), err),
+			}, nil
+		}
+		timings = append(timings, fmt.Sprintf("%s %vms", step.name, elapsed.Milliseconds()))
+	}
+
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusHealthy,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("completed %d steps in %vms (%s)", len(s.steps), total.Milliseconds(), strings.Join(timings, ", ")),
+	}, nil
+}
+
+func (s *HTTPFlowSentinel) runStep(ctx context.Context, client *http.Client, step httpFlowStep, variables map[string]string) (time.Duration, error) {
+	url, err := renderFlowTemplate(step.url, variables)
+	if err != nil {
+		return 0, fmt.Errorf("failed to render url: %v", err)
+	}
+	request, err := step.request.rendered(variables)
+	if err != nil {
+		return 0, fmt.Errorf("failed to render request: %v", err)
+	}
+	req, err := request.newRequest(ctx, url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %v", err)
+	}
+
+	startTime := time.Now()
+	response, err := client.Do(req)
+	if err != nil {
+		return time.Since(startTime), err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	elapsed := time.Since(startTime)
+	if err != nil {
+		return elapsed, fmt.Errorf("error reading body: %v", err)
+	}
+
+	if err := step.checks.evaluate(response, body, elapsed); err != nil {
+		return elapsed, err
+	}
+	for _, extraction := range step.extract {
+		value, err := extraction.apply(response, body)
+		if err != nil {
+			return elapsed, fmt.Errorf("failed to extract %s: %v", extraction.variable, err)
+		}
+		variables[extraction.variable] = value
+	}
+	return elapsed, nil
+}
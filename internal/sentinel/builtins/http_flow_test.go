@@ -0,0 +1,184 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHTTPFlowTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			var credentials map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil || credentials["password"] != "hunter2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s-1", Path: "/"})
+			w.Header().Set("X-Account-Id", "42")
+			w.Write([]byte(`{"data":{"token":"t-1"}}`))
+		case "/accounts/42/orders":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "s-1" || r.Header.Get("Authorization") != "Bearer t-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"orders":[{"id":1},{"id":2}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestHTTPFlowSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"steps": []any{
+					map[string]any{
+						"name":    "login",
+						"url":     "https://example.com/login",
+						"extract": map[string]any{"token": map[string]any{"json": "data.token"}},
+					},
+					map[string]any{"url": "https://example.com/orders", "bearer_token": "{{ .token }}"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing steps",
+			config:      map[string]any{},
+			expectError: true,
+		},
+		{
+			name:        "step without url",
+			config:      map[string]any{"steps": []any{map[string]any{"name": "login"}}},
+			expectError: true,
+		},
+		{
+			name: "invalid template",
+			config: map[string]any{
+				"steps": []any{map[string]any{"url": "https://example.com/{{ .id"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "unsupported extraction source",
+			config: map[string]any{
+				"steps": []any{map[string]any{
+					"url":     "https://example.com",
+					"extract": map[string]any{"token": map[string]any{"xpath": "//token"}},
+				}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHTTPFlowSentinel()
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPFlowSentinel_Check(t *testing.T) {
+	server := newHTTPFlowTestServer()
+	defer server.Close()
+
+	loginStep := func(password string) map[string]any {
+		return map[string]any{
+			"name":   "login",
+			"url":    server.URL + "/login",
+			"method": "POST",
+			"body":   `{"user":"{{ .user }}","password":"` + password + `"}`,
+			"extract": map[string]any{
+				"token":      map[string]any{"json": "data.token"},
+				"account_id": map[string]any{"header": "X-Account-Id"},
+				"session":    map[string]any{"cookie": "session"},
+			},
+		}
+	}
+	listOrdersStep := map[string]any{
+		"name":            "list orders",
+		"url":             server.URL + "/accounts/{{ .account_id }}/orders",
+		"bearer_token":    "{{ .token }}",
+		"json_assertions": []any{map[string]any{"path": "orders.#", "gte": 1}},
+	}
+
+	tests := []struct {
+		name            string
+		steps           []any
+		expectedStatus  signal.Status
+		messageContains string
+	}{
+		{
+			name:            "healthy - login and list orders",
+			steps:           []any{loginStep("hunter2"), listOrdersStep},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "completed 2 steps",
+		},
+		{
+			name:            "unhealthy - login rejected",
+			steps:           []any{loginStep("wrong"), listOrdersStep},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "step 1 (login) failed",
+		},
+		{
+			name: "unhealthy - assertion fails on second step",
+			steps: []any{loginStep("hunter2"), map[string]any{
+				"name":            "list orders",
+				"url":             server.URL + "/accounts/{{ .account_id }}/orders",
+				"bearer_token":    "{{ .token }}",
+				"json_assertions": []any{map[string]any{"path": "orders.#", "gte": 5}},
+			}},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "step 2 (list orders) failed",
+		},
+		{
+			name: "unhealthy - extracted value missing",
+			steps: []any{map[string]any{
+				"name":    "login",
+				"url":     server.URL + "/login",
+				"method":  "POST",
+				"body":    `{"password":"hunter2"}`,
+				"extract": map[string]any{"csrf": map[string]any{"regex": `csrf=(\w+)`}},
+			}},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "failed to extract csrf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHTTPFlowSentinel()
+			require.NoError(t, s.Configure(map[string]any{
+				"variables": map[string]any{"user": "monitor"},
+				"steps":     tt.steps,
+			}))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Contains(t, sig.Message, tt.messageContains)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package builtins
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+const DefaultKafkaTimeout = 10 * time.Second
+
+// KafkaOffsetsClient is the subset of the Kafka admin API used to compute
+// consumer group lag.
+type KafkaOffsetsClient interface {
+	FetchOffsets(ctx context.Context, group string) (kadm.OffsetResponses, error)
+	ListStartOffsets(ctx context.Context, topics ...string) (kadm.ListedOffsets, error)
+	ListEndOffsets(ctx context.Context, topics ...string) (kadm.ListedOffsets, error)
+	Close()
+}
+
+type KafkaLagSentinel struct {
+	group      string
+	topics     []string
+	thresholds thresholds
+	timeout    time.Duration
+	newClient  func() (KafkaOffsetsClient, error)
+}
+
+func NewKafkaLagSentinel() sentinel.Sentinel {
+	return &KafkaLagSentinel{}
+}
+
+func (k *KafkaLagSentinel) Configure(config map[string]any) error {
+	brokers, err := getStringList(config, "brokers")
+	if err != nil {
+		return err
+	}
+	if len(brokers) == 0 {
+		return fmt.Errorf("missing required field: brokers")
+	}
+	if k.group, err = getString(config, "group", ""); err != nil {
+		return err
+	}
+	if k.group == "" {
+		return fmt.Errorf("missing required field: group")
+	}
+	if k.topics, err = getStringList(config, "topics"); err != nil {
+		return err
+	}
+	if k.thresholds, err = parseThresholds(config, "critical", "<="); err != nil {
+		return err
+	}
+	if k.timeout, err = getDuration(config, "timeout", DefaultKafkaTimeout); err != nil {
+		return err
+	}
+
+	options := []kgo.Opt{kgo.SeedBrokers(brokers...), kgo.DialTimeout(k.timeout)}
+	useTLS, err := getBool(config, "tls", false)
+	if err != nil {
+		return err
+	}
+	if useTLS {
+		tlsConfig, err := newTLSClientConfig(config)
+		if err != nil {
+			return err
+		}
+		options = append(options, kgo.DialTLSConfig(tlsConfig))
+	}
+	if raw, ok := config["sasl"]; ok {
+		saslConfig, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("sasl must be a map")
+		}
+		mechanism, err := parseKafkaSASL(saslConfig)
+		if err != nil {
+			return fmt.Errorf("sasl: %v", err)
+		}
+		options = append(options, kgo.SASL(mechanism))
+	}
+	// The client is created per check, as sentinels are never closed.
+	k.newClient = func() (KafkaOffsetsClient, error) {
+		client, err := kgo.NewClient(options...)
+		if err != nil {
+			return nil, err
+		}
+		return kadm.NewClient(client), nil
+	}
+	return nil
+}
+
+func parseKafkaSASL(config map[string]any) (sasl.Mechanism, error) {
+	mechanism, err := getString(config, "mechanism", "PLAIN")
+	if err != nil {
+		return nil, err
+	}
+	username, err := getString(config, "username", "")
+	if err != nil {
+		return nil, err
+	}
+	password, err := getString(config, "password", "")
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, fmt.Errorf("missing required field: username")
+	}
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		return plain.Auth{User: username, Pass: password}.AsMechanism(), nil
+	case "SCRAM-SHA-256":
+		return scram.Auth{User: username, Pass: password}.AsSha256Mechanism(), nil
+	case "SCRAM-SHA-512":
+		return scram.Auth{User: username, Pass: password}.AsSha512Mechanism(), nil
+	default:
+		return nil, fmt.Errorf("unsupported mechanism %s, expected PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512", mechanism)
+	}
+}
+
+func (k *KafkaLagSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	lags, err := k.partitionLags(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	if len(lags) == 0 {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("consumer group %s has no committed offsets", k.group),
+		}, nil
+	}
+
+	highest := slices.MaxFunc(lags, func(a, b labeledValue) int { return cmp.Compare(a.value, b.value) })
+	var total float64
+	for _, lag := range lags {
+		total += lag.value
+	}
+	worst := k.thresholds.evaluateAll(lags)
+	message := k.thresholds.describeAll(lags, worst, "partitions")
+	if worst == thresholdOK {
+		message = fmt.Sprintf("consumer group %s lags %s messages across %d partitions, highest %s=%s",
+			k.group, formatNumber(total), len(lags), highest.label, formatNumber(highest.value))
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics: map[string]float64{
+			"total_lag":  total,
+			"max_lag":    highest.value,
+			"partitions": float64(len(lags)),
+		},
+		Labels: map[string]string{"group": k.group, "max_lag_partition": highest.label},
+	}, nil
+}
+
+// partitionLags returns the lag of the group on every partition of the
+// configured topics, or of the topics it has committed offsets for. A
+// partition without a committed offset lags by all of its retained messages.
+func (k *KafkaLagSentinel) partitionLags(ctx context.Context) ([]labeledValue, error) {
+	client, err := k.newClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %v", err)
+	}
+	defer client.Close()
+
+	commits, err := client.FetchOffsets(ctx, k.group)
+	if err == nil {
+		err = commits.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offsets of group %s: %v", k.group, err)
+	}
+	topics := k.topics
+	if len(topics) == 0 {
+		for topic := range commits {
+			topics = append(topics, topic)
+		}
+		if len(topics) == 0 {
+			return nil, nil
+		}
+	}
+
+	startOffsets, err := client.ListStartOffsets(ctx, topics...)
+	if err == nil {
+		err = startOffsets.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list start offsets: %v", err)
+	}
+	endOffsets, err := client.ListEndOffsets(ctx, topics...)
+	if err == nil {
+		err = endOffsets.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list end offsets: %v", err)
+	}
+
+	var partitions []kadm.ListedOffset
+	endOffsets.Each(func(end kadm.ListedOffset) {
+		partitions = append(partitions, end)
+	})
+	slices.SortFunc(partitions, func(a, b kadm.ListedOffset) int {
+		return cmp.Or(strings.Compare(a.Topic, b.Topic), cmp.Compare(a.Partition, b.Partition))
+	})
+	lags := make([]labeledValue, 0, len(partitions))
+	for _, end := range partitions {
+		committed := int64(-1)
+		if commit, ok := commits.Lookup(end.Topic, end.Partition); ok {
+			committed = commit.At
+		}
+		if committed < 0 {
+			if start, ok := startOffsets.Lookup(end.Topic, end.Partition); ok {
+				committed = start.Offset
+			}
+		}
+		lags = append(lags, labeledValue{
+			label: fmt.Sprintf("%s[%d]", end.Topic, end.Partition),
+			value: float64(max(end.Offset-committed, 0)),
+		})
+	}
+	return lags, nil
+}
@@ -0,0 +1,194 @@
+package builtins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// MockKafkaOffsetsClient serves committed offsets per group and the start and
+// end offsets of each partition, keyed by topic.
+type MockKafkaOffsetsClient struct {
+	commits map[string]map[string][]int64
+	start   map[string][]int64
+	end     map[string][]int64
+}
+
+func (m *MockKafkaOffsetsClient) FetchOffsets(ctx context.Context, group string) (kadm.OffsetResponses, error) {
+	responses := kadm.OffsetResponses{}
+	for topic, offsets := range m.commits[group] {
+		responses[topic] = map[int32]kadm.OffsetResponse{}
+		for partition, at := range offsets {
+			responses[topic][int32(partition)] = kadm.OffsetResponse{Offset: kadm.Offset{Topic: topic, Partition: int32(partition), At: at}}
+		}
+	}
+	return responses, nil
+}
+
+func (m *MockKafkaOffsetsClient) ListStartOffsets(ctx context.Context, topics ...string) (kadm.ListedOffsets, error) {
+	return listedOffsets(m.start, topics), nil
+}
+
+func (m *MockKafkaOffsetsClient) ListEndOffsets(ctx context.Context, topics ...string) (kadm.ListedOffsets, error) {
+	return listedOffsets(m.end, topics), nil
+}
+
+func (m *MockKafkaOffsetsClient) Close() {}
+
+func listedOffsets(offsets map[string][]int64, topics []string) kadm.ListedOffsets {
+	listed := kadm.ListedOffsets{}
+	for _, topic := range topics {
+		partitions, ok := offsets[topic]
+		if !ok {
+			listed[topic] = map[int32]kadm.ListedOffset{0: {Topic: topic, Err: kerr.UnknownTopicOrPartition}}
+			continue
+		}
+		listed[topic] = map[int32]kadm.ListedOffset{}
+		for partition, offset := range partitions {
+			listed[topic][int32(partition)] = kadm.ListedOffset{Topic: topic, Partition: int32(partition), Offset: offset}
+		}
+	}
+	return listed
+}
+
+func TestKafkaLagSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"brokers":  []any{"kafka-1:9092", "kafka-2:9092"},
+				"group":    "billing",
+				"topics":   []any{"invoices"},
+				"critical": 10000,
+				"warning":  1000,
+				"tls":      true,
+				"sasl":     map[string]any{"mechanism": "SCRAM-SHA-512", "username": "monitor", "password": "secret"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing brokers",
+			config:      map[string]any{"group": "billing", "critical": 10000},
+			expectError: true,
+		},
+		{
+			name:        "missing group",
+			config:      map[string]any{"brokers": []any{"kafka-1:9092"}, "critical": 10000},
+			expectError: true,
+		},
+		{
+			name:        "missing critical",
+			config:      map[string]any{"brokers": []any{"kafka-1:9092"}, "group": "billing"},
+			expectError: true,
+		},
+		{
+			name: "unsupported SASL mechanism",
+			config: map[string]any{
+				"brokers":  []any{"kafka-1:9092"},
+				"group":    "billing",
+				"critical": 10000,
+				"sasl":     map[string]any{"mechanism": "GSSAPI", "username": "monitor"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewKafkaLagSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKafkaLagSentinel_Check(t *testing.T) {
+	client := &MockKafkaOffsetsClient{
+		commits: map[string]map[string][]int64{
+			"billing":  {"invoices": {1200, 5000, 800}},
+			"shipping": {"orders": {100, -1}},
+		},
+		start: map[string][]int64{"invoices": {0, 0, 0}, "orders": {0, 40}, "refunds": {10, 10}},
+		end:   map[string][]int64{"invoices": {1250, 17000, 2800}, "orders": {150, 90}, "refunds": {10, 25}},
+	}
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name:            "lag within thresholds",
+			config:          map[string]any{"group": "billing", "critical": 20000},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "consumer group billing lags 14050 messages across 3 partitions, highest invoices[1]=12000",
+			expectedMetrics: map[string]float64{"total_lag": 14050, "max_lag": 12000, "partitions": 3},
+		},
+		{
+			name:            "lagging partitions",
+			config:          map[string]any{"group": "billing", "critical": 10000, "warning": 1000},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: 1 of 3 partitions outside thresholds (expected <= 10000): invoices[1]=12000; warning: 1 of 3 partitions outside thresholds (expected <= 1000): invoices[2]=2000",
+			expectedMetrics: map[string]float64{"total_lag": 14050, "max_lag": 12000, "partitions": 3},
+		},
+		{
+			name:            "partition without committed offset",
+			config:          map[string]any{"group": "shipping", "critical": 40},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: 2 of 2 partitions outside thresholds (expected <= 40): orders[0]=50, orders[1]=50",
+			expectedMetrics: map[string]float64{"total_lag": 100, "max_lag": 50, "partitions": 2},
+		},
+		{
+			name:            "configured topic the group never consumed",
+			config:          map[string]any{"group": "shipping", "topics": []any{"refunds"}, "critical": 10},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: 1 of 2 partitions outside thresholds (expected <= 10): refunds[1]=15",
+			expectedMetrics: map[string]float64{"total_lag": 15, "max_lag": 15, "partitions": 2},
+		},
+		{
+			name:            "unknown group",
+			config:          map[string]any{"group": "analytics", "critical": 10},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "consumer group analytics has no committed offsets",
+		},
+		{
+			name:            "unknown topic",
+			config:          map[string]any{"group": "billing", "topics": []any{"payments"}, "critical": 10},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "failed to list start offsets: " + kerr.UnknownTopicOrPartition.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["brokers"] = []any{"kafka-1:9092"}
+			s := NewKafkaLagSentinel().(*KafkaLagSentinel)
+			require.NoError(t, s.Configure(tt.config))
+			s.newClient = func() (KafkaOffsetsClient, error) { return client, nil }
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+			if tt.expectedMetrics != nil {
+				assert.Equal(t, tt.config["group"], sig.Labels["group"])
+			}
+		})
+	}
+}
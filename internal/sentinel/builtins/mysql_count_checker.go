@@ -88,6 +88,7 @@ func (s *MySQLCountCheckerSentinel) Check(ctx context.Context, alarmID string) (
 			Status:    signal.StatusHealthy,
 			Timestamp: time.Now(),
 			Message:   fmt.Sprintf("query returned %v", response),
+			Metrics:   map[string]float64{"count": float64(response)},
 		}, nil
 	}
 
@@ -96,5 +97,6 @@ func (s *MySQLCountCheckerSentinel) Check(ctx context.Context, alarmID string) (
 		Status:    signal.StatusUnhealthy,
 		Timestamp: time.Now(),
 		Message:   fmt.Sprintf("query returned %v, expected %v", response, s.expected),
+		Metrics:   map[string]float64{"count": float64(response)},
 	}, nil
 }
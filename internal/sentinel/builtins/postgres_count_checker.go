@@ -91,6 +91,7 @@ func (s *PostgresCountCheckerSentinel) Check(ctx context.Context, alarmID string
 			Status:    signal.StatusHealthy,
 			Timestamp: time.Now(),
 			Message:   fmt.Sprintf("query returned %v", response),
+			Metrics:   map[string]float64{"count": float64(response)},
 		}, nil
 	}
 
@@ -99,5 +100,6 @@ func (s *PostgresCountCheckerSentinel) Check(ctx context.Context, alarmID string
 		Status:    signal.StatusUnhealthy,
 		Timestamp: time.Now(),
 		Message:   fmt.Sprintf("query returned %v, expected %v", response, s.expected),
+		Metrics:   map[string]float64{"count": float64(response)},
 	}, nil
 }
@@ -0,0 +1,224 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// maxPrometheusResponseSize bounds how much of a query response is read.
+const maxPrometheusResponseSize = 10 << 20
+
+type PrometheusQuerySentinel struct {
+	url         string
+	query       string
+	label       string
+	emptyResult signal.Status
+	thresholds  thresholds
+	request     httpRequestConfig
+	client      *http.Client
+}
+
+func NewPrometheusQuerySentinel() sentinel.Sentinel {
+	return &PrometheusQuerySentinel{}
+}
+
+func (p *PrometheusQuerySentinel) Configure(config map[string]any) error {
+	for _, field := range []string{"url", "query"} {
+		if _, ok := config[field]; !ok {
+			return fmt.Errorf("missing required field: %s", field)
+		}
+	}
+	baseURL, err := getString(config, "url", "")
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/v1/query")
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("url must use http or https: %s", baseURL)
+	}
+	p.url = endpoint.String()
+	if p.query, err = getString(config, "query", ""); err != nil {
+		return err
+	}
+	if p.label, err = getString(config, "label", ""); err != nil {
+		return err
+	}
+	emptyResult, err := getString(config, "empty_result", string(signal.StatusUnknown))
+	if err != nil {
+		return err
+	}
+	p.emptyResult = signal.Status(emptyResult)
+	if !p.emptyResult.IsValid() {
+		return fmt.Errorf("unsupported empty_result %s, expected one of %v", emptyResult, signal.Statuses)
+	}
+	if p.thresholds, err = parseThresholds(config, "critical", "<="); err != nil {
+		return err
+	}
+	if p.request, err = parseHTTPRequestConfig(config); err != nil {
+		return err
+	}
+	if p.request.method != http.MethodGet || p.request.body != "" {
+		return fmt.Errorf("method and body are not supported, queries are sent as GET requests")
+	}
+	if p.client, err = newHTTPClient(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *PrometheusQuerySentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	results, err := p.runQuery(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	if len(results) == 0 {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    p.emptyResult,
+			Timestamp: time.Now(),
+			Message:   "query returned no series",
+			Metrics:   map[string]float64{"series": 0},
+		}, nil
+	}
+
+	worst := p.thresholds.evaluateAll(results)
+	metrics := resultMetrics(results, p.label != "")
+	metrics["series"] = float64(len(results))
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   p.thresholds.describeResults(results, worst, p.label != "", "series"),
+		Metrics:   metrics,
+	}, nil
+}
+
+// prometheusResponse is the envelope of the Prometheus HTTP API.
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []any             `json:"value"`
+}
+
+func (p *PrometheusQuerySentinel) runQuery(ctx context.Context) ([]labeledValue, error) {
+	req, err := p.request.newRequest(ctx, p.url+"?"+url.Values{"query": {p.query}}.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %v", err)
+	}
+	response, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxPrometheusResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var result prometheusResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("query failed with status %d", response.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", result.ErrorType, result.Error)
+	}
+
+	switch result.Data.ResultType {
+	case "scalar":
+		var sample []any
+		if err := json.Unmarshal(result.Data.Result, &sample); err != nil {
+			return nil, fmt.Errorf("failed to decode scalar result: %v", err)
+		}
+		value, err := prometheusSampleValue(sample)
+		if err != nil {
+			return nil, err
+		}
+		return []labeledValue{{label: "scalar", value: value}}, nil
+	case "vector":
+		var samples []prometheusSample
+		if err := json.Unmarshal(result.Data.Result, &samples); err != nil {
+			return nil, fmt.Errorf("failed to decode vector result: %v", err)
+		}
+		results := make([]labeledValue, 0, len(samples))
+		for _, sample := range samples {
+			value, err := prometheusSampleValue(sample.Value)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, labeledValue{label: p.seriesLabel(sample.Metric), value: value})
+		}
+		return results, nil
+	default:
+		return nil, fmt.Errorf("unsupported result type %s, expected scalar or vector", result.Data.ResultType)
+	}
+}
+
+// prometheusSampleValue reads the value of a `[<unix time>, "<value>"]` pair.
+func prometheusSampleValue(sample []any) (float64, error) {
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("malformed sample: %v", sample)
+	}
+	s, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("malformed sample value: %v", sample[1])
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed sample value: %s", s)
+	}
+	return value, nil
+}
+
+// seriesLabel names a series by the configured label, or by its full label
+// set, e.g. `{instance="api-1:9090",job="api"}`.
+func (p *PrometheusQuerySentinel) seriesLabel(metric map[string]string) string {
+	if p.label != "" {
+		if value, ok := metric[p.label]; ok {
+			return value
+		}
+	}
+	names := make([]string, 0, len(metric))
+	for name := range metric {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "__name__" {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, metric[name]))
+	}
+	return metric["__name__"] + "{" + strings.Join(pairs, ",") + "}"
+}
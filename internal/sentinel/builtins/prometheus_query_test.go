@@ -0,0 +1,174 @@
+package builtins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPrometheusTestServer(t *testing.T) *httptest.Server {
+	responses := map[string]string{
+		"scalar(error_rate)": `{"status":"success","data":{"resultType":"scalar","result":[1717236000,"0.02"]}}`,
+		"queue_depth": `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"__name__":"queue_depth","queue":"emails"},"value":[1717236000,"12"]},
+			{"metric":{"__name__":"queue_depth","queue":"sms"},"value":[1717236000,"80"]},
+			{"metric":{"__name__":"queue_depth","queue":"push"},"value":[1717236000,"250"]}]}}`,
+		"up == 0":       `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		`{"api"}`:       `{"status":"error","errorType":"bad_data","error":"parse error at char 1"}`,
+		"latency_p99ms": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1717236000,"420"]}]}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response, ok := responses[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("query") == `{"api"}` {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPrometheusQuerySentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name:        "valid configuration",
+			config:      map[string]any{"url": "http://prometheus:9090", "query": "up", "operator": ">=", "critical": 1},
+			expectError: false,
+		},
+		{
+			name:        "missing query",
+			config:      map[string]any{"url": "http://prometheus:9090", "critical": 1},
+			expectError: true,
+		},
+		{
+			name:        "missing critical threshold",
+			config:      map[string]any{"url": "http://prometheus:9090", "query": "up"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported scheme",
+			config:      map[string]any{"url": "ftp://prometheus:9090", "query": "up", "critical": 1},
+			expectError: true,
+		},
+		{
+			name:        "post method",
+			config:      map[string]any{"url": "http://prometheus:9090", "query": "up", "critical": 1, "method": "POST"},
+			expectError: true,
+		},
+		{
+			name:        "invalid empty_result",
+			config:      map[string]any{"url": "http://prometheus:9090", "query": "up", "critical": 1, "empty_result": "ok"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPrometheusQuerySentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPrometheusQuerySentinel_Check(t *testing.T) {
+	server := newPrometheusTestServer(t)
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name:            "scalar above warning",
+			config:          map[string]any{"query": "scalar(error_rate)", "critical": 0.05, "warning": 0.01},
+			expectedStatus:  signal.StatusWarning,
+			expectedMessage: "warning: query returned 0.02, expected <= 0.01",
+			expectedMetrics: map[string]float64{"value": 0.02, "series": 1},
+		},
+		{
+			name:            "single series above critical",
+			config:          map[string]any{"query": "latency_p99ms", "critical": 300},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: query returned 420, expected <= 300",
+			expectedMetrics: map[string]float64{"value": 420, "series": 1},
+		},
+		{
+			name:           "vector with offending series",
+			config:         map[string]any{"query": "queue_depth", "label": "queue", "critical": 100, "warning": 50},
+			expectedStatus: signal.StatusUnhealthy,
+			expectedMessage: "critical: 1 of 3 series outside thresholds (expected <= 100): push=250; " +
+				"warning: 1 of 3 series outside thresholds (expected <= 50): sms=80",
+			expectedMetrics: map[string]float64{"emails": 12, "sms": 80, "push": 250, "series": 3},
+		},
+		{
+			name:            "series named by label set",
+			config:          map[string]any{"query": "queue_depth", "operator": "between", "critical": []any{0, 50}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: `critical: 2 of 3 series outside thresholds (expected between 0 and 50): queue_depth{queue="sms"}=80, queue_depth{queue="push"}=250`,
+			expectedMetrics: map[string]float64{`queue_depth{queue="emails"}`: 12, `queue_depth{queue="sms"}`: 80, `queue_depth{queue="push"}`: 250, "series": 3},
+		},
+		{
+			name:            "empty result",
+			config:          map[string]any{"query": "up == 0", "critical": 0, "empty_result": "healthy"},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "query returned no series",
+			expectedMetrics: map[string]float64{"series": 0},
+		},
+		{
+			name:            "query error",
+			config:          map[string]any{"query": `{"api"}`, "critical": 1},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "query failed: bad_data: parse error at char 1",
+		},
+		{
+			name:            "unavailable",
+			config:          map[string]any{"query": "missing", "critical": 1},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "query failed with status 503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["url"] = server.URL + "/"
+			tt.config["bearer_token"] = "secret"
+			s := NewPrometheusQuerySentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// maxRabbitMQResponseSize bounds how much of a management API response is read.
+const maxRabbitMQResponseSize = 1 << 20
+
+type RabbitMQQueueSentinel struct {
+	url       string
+	vhost     string
+	queue     string
+	messages  *thresholds
+	unacked   *thresholds
+	consumers *thresholds
+	request   httpRequestConfig
+	client    *http.Client
+}
+
+func NewRabbitMQQueueSentinel() sentinel.Sentinel {
+	return &RabbitMQQueueSentinel{}
+}
+
+func (r *RabbitMQQueueSentinel) Configure(config map[string]any) error {
+	for _, field := range []string{"url", "queue"} {
+		if _, ok := config[field]; !ok {
+			return fmt.Errorf("missing required field: %s", field)
+		}
+	}
+	baseURL, err := getString(config, "url", "")
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("url must use http or https: %s", baseURL)
+	}
+	r.url = endpoint.String()
+	if r.vhost, err = getString(config, "vhost", "/"); err != nil {
+		return err
+	}
+	if r.queue, err = getString(config, "queue", ""); err != nil {
+		return err
+	}
+	if r.messages, err = parseNestedThresholds(config, "messages", "<=", false); err != nil {
+		return err
+	}
+	if r.unacked, err = parseNestedThresholds(config, "unacked", "<=", false); err != nil {
+		return err
+	}
+	if r.consumers, err = parseNestedThresholds(config, "consumers", ">=", false); err != nil {
+		return err
+	}
+	if r.messages == nil && r.unacked == nil && r.consumers == nil {
+		return fmt.Errorf("at least one of messages, unacked or consumers is required")
+	}
+	if r.request, err = parseHTTPRequestConfig(config); err != nil {
+		return err
+	}
+	if r.request.method != http.MethodGet || r.request.body != "" {
+		return fmt.Errorf("method and body are not supported")
+	}
+	if r.client, err = newHTTPClient(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// rabbitMQQueue holds the fields of the management API's queue object used by
+// the checks.
+type rabbitMQQueue struct {
+	Messages               int64 `json:"messages"`
+	MessagesReady          int64 `json:"messages_ready"`
+	MessagesUnacknowledged int64 `json:"messages_unacknowledged"`
+	Consumers              int64 `json:"consumers"`
+}
+
+func (r *RabbitMQQueueSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	queue, found, err := r.fetchQueue(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	if !found {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("queue %s not found in vhost %s", r.queue, r.vhost),
+		}, nil
+	}
+
+	worst := thresholdOK
+	var observations, failures []string
+	evaluate := func(t *thresholds, value int64, description string) {
+		if t == nil {
+			return
+		}
+		observations = append(observations, description)
+		if level := t.evaluate(float64(value)); level != thresholdOK {
+			worst = max(worst, level)
+			failures = append(failures, fmt.Sprintf("%s: %s, %s", level, description, t.describe(level)))
+		}
+	}
+	evaluate(r.messages, queue.Messages, fmt.Sprintf("queue %s has %d messages (%d ready)", r.queue, queue.Messages, queue.MessagesReady))
+	evaluate(r.unacked, queue.MessagesUnacknowledged, fmt.Sprintf("%d unacked messages", queue.MessagesUnacknowledged))
+	evaluate(r.consumers, queue.Consumers, fmt.Sprintf("%d consumers", queue.Consumers))
+
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = strings.Join(observations, ", ")
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics: map[string]float64{
+			"messages":       float64(queue.Messages),
+			"messages_ready": float64(queue.MessagesReady),
+			"unacked":        float64(queue.MessagesUnacknowledged),
+			"consumers":      float64(queue.Consumers),
+		},
+		Labels: map[string]string{"vhost": r.vhost, "queue": r.queue},
+	}, nil
+}
+
+func (r *RabbitMQQueueSentinel) fetchQueue(ctx context.Context) (rabbitMQQueue, bool, error) {
+	var queue rabbitMQQueue
+	// The default vhost is named "/", so it must be escaped like any other.
+	req, err := r.request.newRequest(ctx, fmt.Sprintf("%s/api/queues/%s/%s", r.url, url.PathEscape(r.vhost), url.PathEscape(r.queue)))
+	if err != nil {
+		return queue, false, fmt.Errorf("failed to build request: %v", err)
+	}
+	response, err := r.client.Do(req)
+	if err != nil {
+		return queue, false, fmt.Errorf("failed to query the management API: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return queue, false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return queue, false, fmt.Errorf("management API returned status %d", response.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxRabbitMQResponseSize))
+	if err != nil {
+		return queue, false, fmt.Errorf("failed to read response: %v", err)
+	}
+	if err := json.Unmarshal(body, &queue); err != nil {
+		return queue, false, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return queue, true, nil
+}
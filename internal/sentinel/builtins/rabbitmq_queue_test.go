@@ -0,0 +1,174 @@
+package builtins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRabbitMQTestServer(t *testing.T) *httptest.Server {
+	queues := map[string]string{
+		"/api/queues/%2F/emails":         `{"name":"emails","vhost":"/","messages":1520,"messages_ready":1500,"messages_unacknowledged":20,"consumers":4}`,
+		"/api/queues/billing/invoices":   `{"name":"invoices","vhost":"billing","messages":3,"messages_ready":0,"messages_unacknowledged":3,"consumers":1}`,
+		"/api/queues/billing/stalled":    `{"name":"stalled","vhost":"billing","messages":90,"messages_ready":0,"messages_unacknowledged":90,"consumers":0}`,
+		"/api/queues/%2F/fresh-no-stats": `{"name":"fresh-no-stats","vhost":"/","consumers":2}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "monitor" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response, ok := queues[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Object Not Found","reason":"Not Found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRabbitMQQueueSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"url":        "http://rabbitmq:15672",
+				"vhost":      "billing",
+				"queue":      "invoices",
+				"messages":   map[string]any{"critical": 10000, "warning": 1000},
+				"unacked":    map[string]any{"critical": 500},
+				"consumers":  map[string]any{"critical": 1},
+				"basic_auth": map[string]any{"username": "monitor", "password": "secret"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing queue",
+			config:      map[string]any{"url": "http://rabbitmq:15672", "messages": map[string]any{"critical": 10000}},
+			expectError: true,
+		},
+		{
+			name:        "no checks",
+			config:      map[string]any{"url": "http://rabbitmq:15672", "queue": "invoices"},
+			expectError: true,
+		},
+		{
+			name:        "invalid url",
+			config:      map[string]any{"url": "amqp://rabbitmq:5672", "queue": "invoices", "consumers": map[string]any{"critical": 1}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRabbitMQQueueSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRabbitMQQueueSentinel_Check(t *testing.T) {
+	server := newRabbitMQTestServer(t)
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name: "healthy queue",
+			config: map[string]any{
+				"vhost":     "billing",
+				"queue":     "invoices",
+				"messages":  map[string]any{"critical": 1000},
+				"unacked":   map[string]any{"critical": 100},
+				"consumers": map[string]any{"critical": 1},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "queue invoices has 3 messages (0 ready), 3 unacked messages, 1 consumers",
+			expectedMetrics: map[string]float64{"messages": 3, "messages_ready": 0, "unacked": 3, "consumers": 1},
+		},
+		{
+			name:            "backlog in the default vhost",
+			config:          map[string]any{"queue": "emails", "messages": map[string]any{"critical": 10000, "warning": 1000}},
+			expectedStatus:  signal.StatusWarning,
+			expectedMessage: "warning: queue emails has 1520 messages (1500 ready), expected <= 1000",
+			expectedMetrics: map[string]float64{"messages": 1520, "messages_ready": 1500, "unacked": 20, "consumers": 4},
+		},
+		{
+			name: "no consumers",
+			config: map[string]any{
+				"vhost":     "billing",
+				"queue":     "stalled",
+				"unacked":   map[string]any{"critical": 50},
+				"consumers": map[string]any{"critical": 1},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: 90 unacked messages, expected <= 50; critical: 0 consumers, expected >= 1",
+			expectedMetrics: map[string]float64{"messages": 90, "messages_ready": 0, "unacked": 90, "consumers": 0},
+		},
+		{
+			name:            "queue without message stats",
+			config:          map[string]any{"queue": "fresh-no-stats", "messages": map[string]any{"critical": 10}},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "queue fresh-no-stats has 0 messages (0 ready)",
+			expectedMetrics: map[string]float64{"messages": 0, "messages_ready": 0, "unacked": 0, "consumers": 2},
+		},
+		{
+			name:            "missing queue",
+			config:          map[string]any{"queue": "sms", "consumers": map[string]any{"critical": 1}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "queue sms not found in vhost /",
+		},
+		{
+			name: "wrong credentials",
+			config: map[string]any{
+				"queue":      "emails",
+				"consumers":  map[string]any{"critical": 1},
+				"basic_auth": map[string]any{"username": "guest", "password": "guest"},
+			},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "management API returned status 401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["url"] = server.URL
+			if _, ok := tt.config["basic_auth"]; !ok {
+				tt.config["basic_auth"] = map[string]any{"username": "monitor", "password": "secret"}
+			}
+			s := NewRabbitMQQueueSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+			if tt.expectedMetrics != nil {
+				assert.Equal(t, tt.config["queue"], sig.Labels["queue"])
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package builtins
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultRedisTimeout = 10 * time.Second
+
+var redisKeyChecks = []string{"length", "exists", "absent", "ttl"}
+
+type RedisCheckerSentinel struct {
+	connection     connections.RedisConnectionConfig
+	timeout        time.Duration
+	maxPingLatency time.Duration
+	infoAssertions []valueAssertion
+	keys           []redisKeyCheck
+}
+
+// redisKeyCheck asserts on a single key: its length, whether it exists, or
+// its TTL in seconds.
+type redisKeyCheck struct {
+	key        string
+	check      string
+	thresholds thresholds
+}
+
+func NewRedisCheckerSentinel() sentinel.Sentinel {
+	return &RedisCheckerSentinel{}
+}
+
+func (s *RedisCheckerSentinel) Configure(config map[string]any) error {
+	connConfig, ok := config["connection"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("missing required field: connection")
+	}
+	redisConfig, err := connections.NewRedisConnectionConfig(connConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create Redis connection config: %v", err)
+	}
+	useTLS, err := getBool(connConfig, "tls", false)
+	if err != nil {
+		return err
+	}
+	if useTLS {
+		if redisConfig.TLS, err = newTLSClientConfig(connConfig); err != nil {
+			return err
+		}
+	}
+	s.connection = *redisConfig
+
+	if s.timeout, err = getDuration(config, "timeout", DefaultRedisTimeout); err != nil {
+		return err
+	}
+	if s.maxPingLatency, err = getDuration(config, "max_ping_latency", 0); err != nil {
+		return err
+	}
+	if s.infoAssertions, err = parseValueAssertions(config, "info_assertions", "field"); err != nil {
+		return err
+	}
+	if s.keys, err = parseRedisKeyChecks(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseRedisKeyChecks(config map[string]any) ([]redisKeyCheck, error) {
+	raw, ok := config["keys"]
+	if !ok {
+		return nil, nil
+	}
+	entries, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("keys must be a list")
+	}
+	checks := make([]redisKeyCheck, 0, len(entries))
+	for i, entry := range entries {
+		entryConfig, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("keys[%d] must be a map", i)
+		}
+		var c redisKeyCheck
+		var err error
+		if c.key, err = getString(entryConfig, "key", ""); err != nil {
+			return nil, fmt.Errorf("keys[%d]: %v", i, err)
+		}
+		if c.key == "" {
+			return nil, fmt.Errorf("keys[%d]: missing required field: key", i)
+		}
+		if c.check, err = getString(entryConfig, "check", "length"); err != nil {
+			return nil, fmt.Errorf("keys[%d]: %v", i, err)
+		}
+		if !slices.Contains(redisKeyChecks, c.check) {
+			return nil, fmt.Errorf("keys[%d]: unsupported check %s, expected one of %v", i, c.check, redisKeyChecks)
+		}
+		if c.check == "length" || c.check == "ttl" {
+			if c.thresholds, err = parseThresholds(entryConfig, "critical", "<="); err != nil {
+				return nil, fmt.Errorf("keys[%d]: %v", i, err)
+			}
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+func (s *RedisCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := connections.NewRedisConnection(s.connection)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	defer conn.Close()
+
+	startTime := time.Now()
+	err = conn.Client.Ping(ctx).Err()
+	latency := time.Since(startTime)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to ping %s: %v", s.connection.Address, err),
+		}, nil
+	}
+
+	metrics := map[string]float64{"latency_ms": float64(latency.Milliseconds())}
+	worst := thresholdOK
+	var failures []string
+	report := func(level thresholdLevel, message string) {
+		worst = max(worst, level)
+		failures = append(failures, fmt.Sprintf("%s: %s", level, message))
+	}
+
+	if s.maxPingLatency > 0 && latency > s.maxPingLatency {
+		report(thresholdCritical, fmt.Sprintf("ping took %vms, expected at most %vms", latency.Milliseconds(), s.maxPingLatency.Milliseconds()))
+	}
+
+	if len(s.infoAssertions) > 0 {
+		info, err := conn.Client.Info(ctx).Result()
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("failed to read INFO: %v", err),
+			}, nil
+		}
+		fields := parseRedisInfo(info)
+		for _, assertion := range s.infoAssertions {
+			value, found := fields[assertion.target]
+			if number, ok := toFloat64(value); found && ok {
+				metrics[assertion.target] = number
+			}
+			if err := assertion.evaluate(value, found); err != nil {
+				report(thresholdCritical, err.Error())
+			}
+		}
+	}
+
+	for _, key := range s.keys {
+		level, message, err := key.evaluate(ctx, conn.Client, metrics)
+		if err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnknown,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("failed to check key %s: %v", key.key, err),
+			}, nil
+		}
+		if level != thresholdOK {
+			report(level, message)
+		}
+	}
+
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = fmt.Sprintf("responded to ping in %vms", latency.Milliseconds())
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+		Labels:    map[string]string{"address": s.connection.Address},
+	}, nil
+}
+
+// evaluate checks the key, recording its TTL or length in metrics as
+// "ttl_seconds:<key>" or "length:<key>".
+func (c redisKeyCheck) evaluate(ctx context.Context, client *redis.Client, metrics map[string]float64) (thresholdLevel, string, error) {
+	switch c.check {
+	case "exists", "absent":
+		exists, err := client.Exists(ctx, c.key).Result()
+		if err != nil {
+			return thresholdOK, "", err
+		}
+		if c.check == "exists" && exists == 0 {
+			return thresholdCritical, fmt.Sprintf("key %s does not exist", c.key), nil
+		}
+		if c.check == "absent" && exists > 0 {
+			return thresholdCritical, fmt.Sprintf("key %s exists", c.key), nil
+		}
+		return thresholdOK, "", nil
+	case "ttl":
+		ttl, err := client.PTTL(ctx, c.key).Result()
+		if err != nil {
+			return thresholdOK, "", err
+		}
+		// go-redis passes PTTL's -2 for missing keys and -1 for keys without
+		// an expiry through as is. Keys without an expiry live forever.
+		if ttl == -2 {
+			return thresholdCritical, fmt.Sprintf("key %s does not exist", c.key), nil
+		}
+		if ttl == -1 {
+			level := c.thresholds.evaluate(math.Inf(1))
+			if level == thresholdOK {
+				return level, "", nil
+			}
+			return level, fmt.Sprintf("key %s has no expiry, %s", c.key, c.thresholds.describe(level)), nil
+		}
+		seconds := ttl.Seconds()
+		metrics["ttl_seconds:"+c.key] = seconds
+		level := c.thresholds.evaluate(seconds)
+		if level == thresholdOK {
+			return level, "", nil
+		}
+		return level, fmt.Sprintf("key %s TTL is %ss, %s", c.key, formatNumber(seconds), c.thresholds.describe(level)), nil
+	default:
+		length, err := redisKeyLength(ctx, client, c.key)
+		if err != nil {
+			return thresholdOK, "", err
+		}
+		metrics["length:"+c.key] = float64(length)
+		level := c.thresholds.evaluate(float64(length))
+		if level == thresholdOK {
+			return level, "", nil
+		}
+		return level, fmt.Sprintf("key %s length is %d, %s", c.key, length, c.thresholds.describe(level)), nil
+	}
+}
+
+// redisKeyLength returns the number of elements of a key, whatever its type.
+// Missing keys have a length of 0.
+func redisKeyLength(ctx context.Context, client *redis.Client, key string) (int64, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch keyType {
+	case "none":
+		return 0, nil
+	case "list":
+		return client.LLen(ctx, key).Result()
+	case "stream":
+		return client.XLen(ctx, key).Result()
+	case "set":
+		return client.SCard(ctx, key).Result()
+	case "zset":
+		return client.ZCard(ctx, key).Result()
+	case "hash":
+		return client.HLen(ctx, key).Result()
+	case "string":
+		return client.StrLen(ctx, key).Result()
+	default:
+		return 0, fmt.Errorf("unsupported key type %s", keyType)
+	}
+}
+
+// parseRedisInfo reads the `field:value` lines of an INFO reply.
+func parseRedisInfo(info string) map[string]any {
+	fields := make(map[string]any)
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields[name] = value
+	}
+	return fields
+}
@@ -0,0 +1,324 @@
+package builtins
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRedisKey struct {
+	keyType string
+	length  int64
+	pttl    int64
+}
+
+const fakeRedisInfo = "# Server\r\nredis_version:7.2.4\r\n\r\n# Memory\r\nused_memory:1048576\r\n\r\n" +
+	"# Stats\r\nevicted_keys:12\r\n\r\n# Replication\r\nrole:master\r\nconnected_slaves:1\r\n"
+
+// startFakeRedis runs a server speaking just enough RESP2 for the checker:
+// it rejects HELLO so clients fall back to RESP2, and requires the password
+// "secret".
+func startFakeRedis(t *testing.T, keys map[string]fakeRedisKey) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, keys)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFakeRedis(conn net.Conn, keys map[string]fakeRedisKey) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := false
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(args[0])
+		var key fakeRedisKey
+		if len(args) > 1 {
+			key = keys[args[1]]
+		}
+		var reply string
+		switch {
+		case command == "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case command == "AUTH":
+			if args[len(args)-1] != "secret" {
+				reply = "-WRONGPASS invalid username-password pair\r\n"
+			} else {
+				authenticated = true
+				reply = "+OK\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case command == "SELECT" || command == "CLIENT":
+			reply = "+OK\r\n"
+		case command == "PING":
+			reply = "+PONG\r\n"
+		case command == "INFO":
+			reply = fmt.Sprintf("$%d\r\n%s\r\n", len(fakeRedisInfo), fakeRedisInfo)
+		case command == "TYPE":
+			if key.keyType == "" {
+				key.keyType = "none"
+			}
+			reply = "+" + key.keyType + "\r\n"
+		case command == "LLEN" || command == "XLEN":
+			reply = fmt.Sprintf(":%d\r\n", key.length)
+		case command == "EXISTS":
+			if key.keyType == "" {
+				reply = ":0\r\n"
+			} else {
+				reply = ":1\r\n"
+			}
+		case command == "PTTL":
+			if key.keyType == "" {
+				reply = ":-2\r\n"
+			} else {
+				reply = fmt.Sprintf(":%d\r\n", key.pttl)
+			}
+		default:
+			reply = fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("malformed command: %q", line)
+	}
+	args := make([]string, count)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"connection":      map[string]any{"address": "localhost:6379", "password": "secret", "db": 2, "tls": true},
+				"info_assertions": []any{map[string]any{"field": "role", "equals": "master"}},
+				"keys": []any{
+					map[string]any{"key": "queue:emails", "critical": 1000, "warning": 500},
+					map[string]any{"key": "lock:import", "check": "absent"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing connection",
+			config:      map[string]any{"max_ping_latency": "50ms"},
+			expectError: true,
+		},
+		{
+			name:        "address without port",
+			config:      map[string]any{"connection": map[string]any{"address": "localhost"}},
+			expectError: true,
+		},
+		{
+			name: "unsupported key check",
+			config: map[string]any{
+				"connection": map[string]any{"address": "localhost:6379"},
+				"keys":       []any{map[string]any{"key": "queue:emails", "check": "size"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "length check without threshold",
+			config: map[string]any{
+				"connection": map[string]any{"address": "localhost:6379"},
+				"keys":       []any{map[string]any{"key": "queue:emails"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "info assertion without field",
+			config: map[string]any{
+				"connection":      map[string]any{"address": "localhost:6379"},
+				"info_assertions": []any{map[string]any{"equals": "master"}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRedisCheckerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRedisCheckerSentinel_Check(t *testing.T) {
+	address := startFakeRedis(t, map[string]fakeRedisKey{
+		"queue:emails":   {keyType: "list", length: 12},
+		"queue:webhooks": {keyType: "list", length: 640},
+		"events":         {keyType: "stream", length: 2500},
+		"lock:import":    {keyType: "string", pttl: -1},
+		"session:warm":   {keyType: "string", pttl: 30000},
+	})
+	connection := map[string]any{"address": address, "password": "secret", "db": 1}
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name: "all checks pass",
+			config: map[string]any{
+				"info_assertions": []any{
+					map[string]any{"field": "role", "equals": "master"},
+					map[string]any{"field": "connected_slaves", "gte": 1},
+				},
+				"keys": []any{
+					map[string]any{"key": "queue:emails", "critical": 100},
+					map[string]any{"key": "queue:missing", "critical": 100},
+					map[string]any{"key": "session:warm", "check": "ttl", "operator": ">=", "critical": 10},
+				},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMetrics: map[string]float64{"connected_slaves": 1, "length:queue:emails": 12, "length:queue:missing": 0, "ttl_seconds:session:warm": 30},
+		},
+		{
+			name: "info assertion fails",
+			config: map[string]any{
+				"info_assertions": []any{map[string]any{"field": "evicted_keys", "equals": 0}},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: evicted_keys is 12, expected 0",
+			expectedMetrics: map[string]float64{"evicted_keys": 12},
+		},
+		{
+			name: "list and stream lengths",
+			config: map[string]any{
+				"keys": []any{
+					map[string]any{"key": "queue:webhooks", "critical": 1000, "warning": 500},
+					map[string]any{"key": "events", "critical": 2000},
+				},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+			expectedMessage: "warning: key queue:webhooks length is 640, expected <= 500; " +
+				"critical: key events length is 2500, expected <= 2000",
+			expectedMetrics: map[string]float64{"length:queue:webhooks": 640, "length:events": 2500},
+		},
+		{
+			name: "key existence",
+			config: map[string]any{
+				"keys": []any{
+					map[string]any{"key": "lock:import", "check": "absent"},
+					map[string]any{"key": "heartbeat:etl", "check": "exists"},
+				},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: key lock:import exists; critical: key heartbeat:etl does not exist",
+			expectedMetrics: map[string]float64{},
+		},
+		{
+			name: "ttl",
+			config: map[string]any{
+				"keys": []any{
+					map[string]any{"key": "session:warm", "check": "ttl", "operator": ">=", "critical": 60},
+					map[string]any{"key": "lock:import", "check": "ttl", "critical": 3600},
+				},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: key session:warm TTL is 30s, expected >= 60; critical: key lock:import has no expiry, expected <= 3600",
+			expectedMetrics: map[string]float64{"ttl_seconds:session:warm": 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["connection"] = connection
+			s := NewRedisCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			if tt.expectedMessage != "" {
+				assert.Equal(t, tt.expectedMessage, sig.Message)
+			} else {
+				assert.Contains(t, sig.Message, "responded to ping in")
+			}
+			assert.Contains(t, sig.Metrics, "latency_ms")
+			delete(sig.Metrics, "latency_ms")
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+			assert.Equal(t, map[string]string{"address": address}, sig.Labels)
+		})
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		s := NewRedisCheckerSentinel()
+		require.NoError(t, s.Configure(map[string]any{
+			"connection": map[string]any{"address": address, "password": "wrong"},
+		}))
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+		assert.Contains(t, sig.Message, "WRONGPASS")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		closedAddress := listener.Addr().String()
+		listener.Close()
+
+		s := NewRedisCheckerSentinel()
+		require.NoError(t, s.Configure(map[string]any{"connection": map[string]any{"address": closedAddress}}))
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+		assert.Contains(t, sig.Message, "failed to ping")
+	})
+}
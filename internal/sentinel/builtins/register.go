@@ -6,5 +6,22 @@ func Register(f *sentinel.SentinelFactory) {
 	f.Register("endpoint-checker", NewEndpointCheckerSentinel)
 	f.Register("mysql-count-checker", NewMySQLCountCheckerSentinel)
 	f.Register("sqs-count-checker", NewSQSCountCheckerSentinel)
+	f.Register("sqs-queue-checker", NewSQSQueueCheckerSentinel)
+	f.Register("s3-object-checker", NewS3ObjectCheckerSentinel)
 	f.Register("postgres-count-checker", NewPostgresCountCheckerSentinel)
+	f.Register("tls-cert-checker", NewTLSCertCheckerSentinel)
+	f.Register("tcp-checker", NewTCPCheckerSentinel)
+	f.Register("dns-checker", NewDNSCheckerSentinel)
+	f.Register("http-flow", NewHTTPFlowSentinel)
+	f.Register("sql-checker", NewSQLCheckerSentinel)
+	f.Register("ssh-command", NewSSHCommandSentinel)
+	f.Register("file-checker", NewFileCheckerSentinel)
+	f.Register("exec", NewExecSentinel)
+	f.Register("prometheus-query", NewPrometheusQuerySentinel)
+	f.Register("redis-checker", NewRedisCheckerSentinel)
+	f.Register("grpc-health", NewGRPCHealthSentinel)
+	f.Register("elasticsearch-checker", NewElasticsearchCheckerSentinel)
+	f.Register("rabbitmq-queue", NewRabbitMQQueueSentinel)
+	f.Register("kafka-lag", NewKafkaLagSentinel)
+	f.Register("docker-container", NewDockerContainerSentinel)
 }
@@ -0,0 +1,106 @@
+package builtins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+type responseChecks struct {
+	expectedStatus   int
+	expectedBody     string
+	bodyRegex        *regexp.Regexp
+	jsonAssertions   []valueAssertion
+	headerAssertions []valueAssertion
+	maxResponseTime  time.Duration
+}
+
+func parseResponseChecks(config map[string]any) (responseChecks, error) {
+	var c responseChecks
+	if expectedStatus, ok := config["expected_status"]; ok {
+		if status, ok := expectedStatus.(float64); ok {
+			c.expectedStatus = int(status)
+		} else if status, ok := expectedStatus.(int); ok {
+			c.expectedStatus = status
+		} else {
+			return c, fmt.Errorf("expected_status must be a number")
+		}
+	} else {
+		c.expectedStatus = DefaultExpectedStatus
+	}
+	var err error
+	if c.expectedBody, err = getString(config, "expected_body", ""); err != nil {
+		return c, err
+	}
+	bodyRegex, err := getString(config, "body_regex", "")
+	if err != nil {
+		return c, err
+	}
+	if bodyRegex != "" {
+		c.bodyRegex, err = regexp.Compile(bodyRegex)
+		if err != nil {
+			return c, fmt.Errorf("invalid `body_regex`: %v", err)
+		}
+	}
+	if c.jsonAssertions, err = parseValueAssertions(config, "json_assertions", "path"); err != nil {
+		return c, err
+	}
+	if c.headerAssertions, err = parseValueAssertions(config, "header_assertions", "name"); err != nil {
+		return c, err
+	}
+	if c.maxResponseTime, err = getDuration(config, "max_response_time", 0); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func (c responseChecks) needsBody() bool {
+	return c.expectedBody != "" || c.bodyRegex != nil || len(c.jsonAssertions) > 0
+}
+
+// evaluate returns an error describing the first failed check. body may be nil
+// when needsBody is false.
+func (c responseChecks) evaluate(response *http.Response, body []byte, responseTime time.Duration) error {
+	if response.StatusCode != c.expectedStatus {
+		return fmt.Errorf("expected status %d, got %d", c.expectedStatus, response.StatusCode)
+	}
+
+	if c.maxResponseTime > 0 && responseTime > c.maxResponseTime {
+		return fmt.Errorf("responded in %vms, exceeding the limit of %vms", responseTime.Milliseconds(), c.maxResponseTime.Milliseconds())
+	}
+
+	for _, assertion := range c.headerAssertions {
+		values, found := response.Header[http.CanonicalHeaderKey(assertion.target)]
+		var value any
+		if found {
+			value = values[0]
+		}
+		if err := assertion.evaluate(value, found); err != nil {
+			return fmt.Errorf("header assertion failed: %v", err)
+		}
+	}
+
+	if c.expectedBody != "" && string(body) != c.expectedBody {
+		return fmt.Errorf("expected body %s, got %s", c.expectedBody, string(body))
+	}
+
+	if c.bodyRegex != nil && !c.bodyRegex.Match(body) {
+		return fmt.Errorf("expected body to match %s, got %s", c.bodyRegex.String(), string(body))
+	}
+
+	if len(c.jsonAssertions) > 0 {
+		var document any
+		if err := json.Unmarshal(body, &document); err != nil {
+			return fmt.Errorf("failed to parse body as JSON: %v", err)
+		}
+		for _, assertion := range c.jsonAssertions {
+			value, found := lookupJSONPath(document, assertion.target)
+			if err := assertion.evaluate(value, found); err != nil {
+				return fmt.Errorf("JSON assertion failed: %v", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+type S3ObjectCheckerSentinel struct {
+	bucket           string
+	prefix           string
+	keyRegex         *regexp.Regexp
+	newestObjectAge  *thresholds
+	newestObjectSize *thresholds
+	objectCount      *thresholds
+	client           s3.ListObjectsV2APIClient
+}
+
+func NewS3ObjectCheckerSentinel() sentinel.Sentinel {
+	return &S3ObjectCheckerSentinel{}
+}
+
+func (s *S3ObjectCheckerSentinel) Configure(config map[string]any) error {
+	var err error
+	if s.bucket, err = getString(config, "bucket", ""); err != nil {
+		return err
+	}
+	if s.bucket == "" {
+		return fmt.Errorf("missing required field: bucket")
+	}
+	if s.prefix, err = getString(config, "prefix", ""); err != nil {
+		return err
+	}
+	pattern, err := getString(config, "key_regex", "")
+	if err != nil {
+		return err
+	}
+	if pattern != "" {
+		if s.keyRegex, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid key_regex: %v", err)
+		}
+	}
+	if s.newestObjectAge, err = parseNestedThresholds(config, "newest_object_age", "<=", true); err != nil {
+		return err
+	}
+	if s.newestObjectSize, err = parseNestedThresholds(config, "newest_object_size", ">=", false); err != nil {
+		return err
+	}
+	if s.objectCount, err = parseNestedThresholds(config, "object_count", ">=", false); err != nil {
+		return err
+	}
+	if s.newestObjectAge == nil && s.newestObjectSize == nil && s.objectCount == nil {
+		return fmt.Errorf("at least one of newest_object_age, newest_object_size or object_count is required")
+	}
+
+	cfg, endpointURL, err := loadAWSConfig(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	// S3-compatible stores such as MinIO usually don't resolve bucket
+	// subdomains, so path-style addressing is the default for custom endpoints.
+	usePathStyle, err := getBool(config, "force_path_style", endpointURL != "")
+	if err != nil {
+		return err
+	}
+	s.client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = baseEndpoint(endpointURL)
+		o.UsePathStyle = usePathStyle
+	})
+	return nil
+}
+
+type s3ObjectSummary struct {
+	count        int64
+	newestKey    string
+	newestSize   int64
+	newestUpdate time.Time
+}
+
+func (s *S3ObjectCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	summary, err := s.listObjects(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+
+	location := fmt.Sprintf("s3://%s/%s", s.bucket, s.prefix)
+	metrics := map[string]float64{"objects": float64(summary.count)}
+	labels := map[string]string{"bucket": s.bucket, "prefix": s.prefix}
+	if summary.count == 0 && (s.newestObjectAge != nil || s.newestObjectSize != nil) {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("no objects found in %s", location),
+			Metrics:   metrics,
+			Labels:    labels,
+		}, nil
+	}
+
+	worst := thresholdOK
+	var observations, failures []string
+	evaluate := func(t *thresholds, value float64, description string) {
+		if t == nil {
+			return
+		}
+		level := t.evaluate(value)
+		observations = append(observations, description)
+		if level != thresholdOK {
+			worst = max(worst, level)
+			failures = append(failures, fmt.Sprintf("%s: %s, %s", level, description, t.describe(level)))
+		}
+	}
+	if summary.count > 0 {
+		age := time.Since(summary.newestUpdate)
+		metrics["newest_object_age_seconds"] = age.Seconds()
+		metrics["newest_object_size_bytes"] = float64(summary.newestSize)
+		evaluate(s.newestObjectAge, age.Seconds(), fmt.Sprintf("newest object %s is %v old", summary.newestKey, age.Round(time.Second)))
+		evaluate(s.newestObjectSize, float64(summary.newestSize), fmt.Sprintf("newest object %s is %d bytes", summary.newestKey, summary.newestSize))
+	}
+	evaluate(s.objectCount, float64(summary.count), fmt.Sprintf("%s has %d objects", location, summary.count))
+
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = strings.Join(observations, ", ")
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+		Labels:    labels,
+	}, nil
+}
+
+func (s *S3ObjectCheckerSentinel) listObjects(ctx context.Context) (s3ObjectSummary, error) {
+	var summary s3ObjectSummary
+	input := &s3.ListObjectsV2Input{Bucket: aws.String(s.bucket)}
+	if s.prefix != "" {
+		input.Prefix = aws.String(s.prefix)
+	}
+	paginator := s3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list objects: %v", err)
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if s.keyRegex != nil && !s.keyRegex.MatchString(key) {
+				continue
+			}
+			summary.count++
+			modified := aws.ToTime(object.LastModified)
+			if summary.newestKey == "" || modified.After(summary.newestUpdate) {
+				summary.newestKey = key
+				summary.newestSize = aws.ToInt64(object.Size)
+				summary.newestUpdate = modified
+			}
+		}
+	}
+	return summary, nil
+}
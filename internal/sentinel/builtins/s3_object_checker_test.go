@@ -0,0 +1,222 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockS3Client struct {
+	pages [][]types.Object
+}
+
+// ListObjectsV2 serves one page per call, using the page index as the
+// continuation token.
+func (m *MockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	page := 0
+	if params.ContinuationToken != nil {
+		fmt.Sscan(*params.ContinuationToken, &page)
+	}
+	output := &s3.ListObjectsV2Output{}
+	if page < len(m.pages) {
+		output.Contents = m.pages[page]
+	}
+	if page+1 < len(m.pages) {
+		output.IsTruncated = aws.Bool(true)
+		output.NextContinuationToken = aws.String(fmt.Sprint(page + 1))
+	}
+	return output, nil
+}
+
+func s3Object(key string, size int64, age time.Duration) types.Object {
+	return types.Object{
+		Key:          aws.String(key),
+		Size:         aws.Int64(size),
+		LastModified: aws.Time(time.Now().Add(-age)),
+	}
+}
+
+func TestS3ObjectCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"bucket":             "backups",
+				"prefix":             "postgres/",
+				"aws_region":         "us-east-1",
+				"newest_object_age":  map[string]any{"critical": "26h", "warning": "25h"},
+				"newest_object_size": map[string]any{"critical": 1048576},
+				"endpoint_url":       "http://minio:9000",
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing bucket",
+			config:      map[string]any{"aws_region": "us-east-1", "object_count": map[string]any{"critical": 1}},
+			expectError: true,
+		},
+		{
+			name:        "no checks",
+			config:      map[string]any{"bucket": "backups", "aws_region": "us-east-1"},
+			expectError: true,
+		},
+		{
+			name: "invalid key regex",
+			config: map[string]any{
+				"bucket":       "backups",
+				"aws_region":   "us-east-1",
+				"key_regex":    "(unclosed",
+				"object_count": map[string]any{"critical": 1},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewS3ObjectCheckerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestS3ObjectCheckerSentinel_Check(t *testing.T) {
+	pages := [][]types.Object{
+		{
+			s3Object("postgres/2025-05-30.sql.gz", 52428800, 50*time.Hour),
+			s3Object("postgres/2025-05-31.sql.gz", 51380224, 26*time.Hour),
+		},
+		{
+			s3Object("postgres/2025-06-01.sql.gz", 1024, 2*time.Hour),
+			s3Object("postgres/README", 64, time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		pages           [][]types.Object
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name: "fresh export",
+			config: map[string]any{
+				"key_regex":         `\.sql\.gz$`,
+				"newest_object_age": map[string]any{"critical": "26h"},
+				"object_count":      map[string]any{"critical": 3},
+			},
+			pages:           pages,
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "newest object postgres/2025-06-01.sql.gz is 2h0m0s old, s3://backups/postgres/ has 3 objects",
+			expectedMetrics: map[string]float64{"objects": 3, "newest_object_age_seconds": 7200, "newest_object_size_bytes": 1024},
+		},
+		{
+			name: "truncated dump",
+			config: map[string]any{
+				"key_regex":          `\.sql\.gz$`,
+				"newest_object_size": map[string]any{"critical": 1048576},
+			},
+			pages:           pages,
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: newest object postgres/2025-06-01.sql.gz is 1024 bytes, expected >= 1048576",
+			expectedMetrics: map[string]float64{"objects": 3, "newest_object_age_seconds": 7200, "newest_object_size_bytes": 1024},
+		},
+		{
+			name: "export didn't run",
+			config: map[string]any{
+				"newest_object_age": map[string]any{"critical": "26h"},
+			},
+			pages:           pages[:1],
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: newest object postgres/2025-05-31.sql.gz is 26h0m0s old, expected <= 93600",
+			expectedMetrics: map[string]float64{"objects": 2, "newest_object_age_seconds": 93600, "newest_object_size_bytes": 51380224},
+		},
+		{
+			name: "empty prefix",
+			config: map[string]any{
+				"newest_object_age": map[string]any{"critical": "26h"},
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "no objects found in s3://backups/postgres/",
+			expectedMetrics: map[string]float64{"objects": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["bucket"] = "backups"
+			tt.config["prefix"] = "postgres/"
+			tt.config["aws_region"] = "us-east-1"
+			s := NewS3ObjectCheckerSentinel().(*S3ObjectCheckerSentinel)
+			require.NoError(t, s.Configure(tt.config))
+			s.client = &MockS3Client{pages: tt.pages}
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Len(t, sig.Metrics, len(tt.expectedMetrics))
+			for name, value := range tt.expectedMetrics {
+				assert.InDelta(t, value, sig.Metrics[name], 5, name)
+			}
+			assert.Equal(t, map[string]string{"bucket": "backups", "prefix": "postgres/"}, sig.Labels)
+		})
+	}
+}
+
+func TestS3ObjectCheckerSentinel_CustomEndpoint(t *testing.T) {
+	lastModified := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/backups" || r.URL.Query().Get("list-type") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>backups</Name>
+  <Prefix>%s</Prefix>
+  <KeyCount>1</KeyCount>
+  <IsTruncated>false</IsTruncated>
+  <Contents><Key>postgres/latest.sql.gz</Key><LastModified>%s</LastModified><Size>2048</Size></Contents>
+</ListBucketResult>`, r.URL.Query().Get("prefix"), lastModified)
+	}))
+	defer server.Close()
+
+	s := NewS3ObjectCheckerSentinel()
+	require.NoError(t, s.Configure(map[string]any{
+		"bucket":                "backups",
+		"prefix":                "postgres/",
+		"aws_region":            "us-east-1",
+		"aws_access_key_id":     "minio",
+		"aws_secret_access_key": "minio-secret",
+		"endpoint_url":          server.URL,
+		"newest_object_age":     map[string]any{"critical": "2h"},
+	}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusHealthy, sig.Status)
+	assert.Contains(t, sig.Message, "newest object postgres/latest.sql.gz is 1h0m")
+}
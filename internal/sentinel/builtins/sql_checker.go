@@ -0,0 +1,345 @@
+package builtins
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+var sqlCheckerDrivers = []string{"mysql", "postgres", "sqlite"}
+
+type SQLCheckerSentinel struct {
+	driver      string
+	query       string
+	columns     []sqlColumnCheck
+	labelColumn string
+	rowCount    bool
+	emptyResult signal.Status
+	thresholds  thresholds
+	db          *sql.DB
+	connection  io.Closer
+}
+
+// sqlColumnCheck asserts thresholds on one column of every returned row. An
+// empty column is the first one.
+type sqlColumnCheck struct {
+	column     string
+	thresholds thresholds
+}
+
+func NewSQLCheckerSentinel() sentinel.Sentinel {
+	return &SQLCheckerSentinel{}
+}
+
+func (s *SQLCheckerSentinel) Configure(config map[string]any) error {
+	for _, field := range []string{"driver", "connection", "query"} {
+		if _, ok := config[field]; !ok {
+			return fmt.Errorf("missing required field: %s", field)
+		}
+	}
+	var err error
+	if s.driver, err = getString(config, "driver", ""); err != nil {
+		return err
+	}
+	if !slices.Contains(sqlCheckerDrivers, s.driver) {
+		return fmt.Errorf("unsupported driver %s, expected one of %v", s.driver, sqlCheckerDrivers)
+	}
+	if s.query, err = getString(config, "query", ""); err != nil {
+		return err
+	}
+	column, err := getString(config, "column", "")
+	if err != nil {
+		return err
+	}
+	if s.labelColumn, err = getString(config, "label_column", ""); err != nil {
+		return err
+	}
+	if s.rowCount, err = getBool(config, "row_count", false); err != nil {
+		return err
+	}
+	emptyResult, err := getString(config, "empty_result", string(signal.StatusUnknown))
+	if err != nil {
+		return err
+	}
+	s.emptyResult = signal.Status(emptyResult)
+	if !s.emptyResult.IsValid() {
+		return fmt.Errorf("unsupported empty_result %s, expected one of %v", emptyResult, signal.Statuses)
+	}
+
+	if _, ok := config["columns"]; ok {
+		if column != "" || s.rowCount {
+			return fmt.Errorf("columns can't be combined with column or row_count")
+		}
+		for _, field := range []string{"operator", "critical", "expected", "warning"} {
+			if _, ok := config[field]; ok {
+				return fmt.Errorf("%s can't be combined with columns, set it on each column instead", field)
+			}
+		}
+		if s.columns, err = parseSQLColumnChecks(config); err != nil {
+			return err
+		}
+	} else {
+		if s.thresholds, err = parseSQLThresholds(config); err != nil {
+			return err
+		}
+		if !s.rowCount {
+			s.columns = []sqlColumnCheck{{column: column, thresholds: s.thresholds}}
+		}
+	}
+
+	connConfig, ok := config["connection"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("connection config must be a map")
+	}
+	return s.connect(connConfig)
+}
+
+// parseSQLThresholds reads thresholds, accepting `expected` in place of
+// `critical`.
+func parseSQLThresholds(config map[string]any) (thresholds, error) {
+	criticalField := "critical"
+	if _, ok := config["critical"]; !ok {
+		criticalField = "expected"
+	}
+	return parseThresholds(config, criticalField, "==")
+}
+
+// parseSQLColumnChecks reads `columns`, a list of columns each with its own
+// thresholds, e.g. `{column: lag_seconds, operator: "<=", critical: 60}`.
+func parseSQLColumnChecks(config map[string]any) ([]sqlColumnCheck, error) {
+	entries, ok := config["columns"].([]any)
+	if !ok || len(entries) == 0 {
+		return nil, fmt.Errorf("columns must be a non-empty list")
+	}
+	checks := make([]sqlColumnCheck, 0, len(entries))
+	for i, entry := range entries {
+		entryConfig, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("columns[%d] must be a map", i)
+		}
+		var c sqlColumnCheck
+		var err error
+		if c.column, err = getString(entryConfig, "column", ""); err != nil {
+			return nil, fmt.Errorf("columns[%d]: %v", i, err)
+		}
+		if c.column == "" {
+			return nil, fmt.Errorf("columns[%d]: missing required field: column", i)
+		}
+		if c.thresholds, err = parseSQLThresholds(entryConfig); err != nil {
+			return nil, fmt.Errorf("columns[%d]: %v", i, err)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+func (s *SQLCheckerSentinel) connect(connConfig map[string]any) error {
+	switch s.driver {
+	case "mysql":
+		mysqlConfig, err := connections.NewMySQLConnectionConfig(connConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create MySQL connection config: %v", err)
+		}
+		conn, err := connections.NewMySQLConnection(*mysqlConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create MySQL connection: %v", err)
+		}
+		s.db, s.connection = conn.DB, conn
+	case "postgres":
+		pgConfig, err := connections.NewPostgresConnectionConfig(connConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create Postgres connection config: %v", err)
+		}
+		conn, err := connections.NewPostgresConnection(*pgConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create Postgres connection: %v", err)
+		}
+		s.db, s.connection = conn.DB, conn
+	case "sqlite":
+		sqliteConfig, err := connections.NewSQLiteConnectionConfig(connConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create SQLite connection config: %v", err)
+		}
+		conn, err := connections.NewSQLiteConnection(*sqliteConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create SQLite connection: %v", err)
+		}
+		s.db, s.connection = conn.DB, conn
+	}
+	return nil
+}
+
+func (s *SQLCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	defer s.connection.Close()
+	rowCount, results, err := s.queryRows(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+
+	if s.rowCount {
+		level := s.thresholds.evaluate(float64(rowCount))
+		message := fmt.Sprintf("query returned %d rows", rowCount)
+		if level != thresholdOK {
+			message = fmt.Sprintf("%s: query returned %d rows, %s", level, rowCount, s.thresholds.describe(level))
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    level.status(),
+			Timestamp: time.Now(),
+			Message:   message,
+			Metrics:   map[string]float64{"rows": float64(rowCount)},
+		}, nil
+	}
+
+	if rowCount == 0 {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    s.emptyResult,
+			Timestamp: time.Now(),
+			Message:   "query returned no rows",
+			Metrics:   map[string]float64{"rows": 0},
+		}, nil
+	}
+
+	if len(s.columns) == 1 {
+		check := s.columns[0]
+		worst := check.thresholds.evaluateAll(results[0])
+		metrics := resultMetrics(results[0], s.labelColumn != "")
+		metrics["rows"] = float64(rowCount)
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    worst.status(),
+			Timestamp: time.Now(),
+			Message:   check.thresholds.describeResults(results[0], worst, s.labelColumn != "", "rows"),
+			Metrics:   metrics,
+		}, nil
+	}
+
+	worst := thresholdOK
+	var failures []string
+	metrics := map[string]float64{"rows": float64(rowCount)}
+	for i, check := range s.columns {
+		maps.Copy(metrics, resultMetrics(results[i], true))
+		level := check.thresholds.evaluateAll(results[i])
+		if level != thresholdOK {
+			worst = max(worst, level)
+			failures = append(failures, check.thresholds.describeAll(results[i], level, "rows"))
+		}
+	}
+	message := strings.Join(failures, "; ")
+	if worst == thresholdOK {
+		message = fmt.Sprintf("query returned %d rows, all columns within thresholds", rowCount)
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    worst.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+		Metrics:   metrics,
+	}, nil
+}
+
+// queryRows runs the query and returns the number of rows along with the
+// values of every checked column, in the order of s.columns. With several
+// columns values are labeled after their column, e.g. "lag_seconds[db-2]".
+func (s *SQLCheckerSentinel) queryRows(ctx context.Context) (int, [][]labeledValue, error) {
+	rows, err := s.db.QueryContext(ctx, s.query)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read query columns: %v", err)
+	}
+	valueIndexes := make([]int, len(s.columns))
+	for i, check := range s.columns {
+		if check.column == "" {
+			continue
+		}
+		if valueIndexes[i] = slices.Index(columns, check.column); valueIndexes[i] < 0 {
+			return 0, nil, fmt.Errorf("column %s not found in query result %v", check.column, columns)
+		}
+	}
+	labelIndex := -1
+	if s.labelColumn != "" {
+		if labelIndex = slices.Index(columns, s.labelColumn); labelIndex < 0 {
+			return 0, nil, fmt.Errorf("column %s not found in query result %v", s.labelColumn, columns)
+		}
+	}
+
+	rowCount := 0
+	results := make([][]labeledValue, len(s.columns))
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan query result: %v", err)
+		}
+		rowCount++
+		label := fmt.Sprintf("row %d", rowCount)
+		if labelIndex >= 0 {
+			label = sqlValueString(values[labelIndex])
+		}
+		for i, valueIndex := range valueIndexes {
+			value, ok := sqlValueFloat(values[valueIndex])
+			if !ok {
+				return 0, nil, fmt.Errorf("%s: column %s is not numeric: %v", label, columns[valueIndex], sqlValueString(values[valueIndex]))
+			}
+			result := labeledValue{label: label, value: value}
+			if len(s.columns) > 1 {
+				result.label = fmt.Sprintf("%s[%s]", columns[valueIndex], label)
+			}
+			results[i] = append(results[i], result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to read query result: %v", err)
+	}
+	return rowCount, results, nil
+}
+
+func sqlValueFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return toFloat64(string(v))
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return toFloat64(v)
+	}
+}
+
+func sqlValueString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,358 @@
+package builtins
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLCheckerTestDatabase(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "queues.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE queues (name TEXT, depth INTEGER, error_rate REAL);
+		INSERT INTO queues VALUES ('emails', 12, 0.01), ('sms', 80, 0.02), ('push', 250, 0.5);
+	`)
+	require.NoError(t, err)
+	return path
+}
+
+func TestSQLCheckerSentinel_Configure(t *testing.T) {
+	path := newSQLCheckerTestDatabase(t)
+	connection := map[string]any{"path": path}
+
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT count(*) FROM queues",
+				"operator":   "<=",
+				"critical":   100,
+				"warning":    50,
+			},
+			expectError: false,
+		},
+		{
+			name: "expected is accepted as critical threshold",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT count(*) FROM queues",
+				"expected":   3,
+			},
+			expectError: false,
+		},
+		{
+			name: "unsupported driver",
+			config: map[string]any{
+				"driver":     "oracle",
+				"connection": connection,
+				"query":      "SELECT 1",
+				"critical":   1,
+			},
+			expectError: true,
+		},
+		{
+			name: "unsupported operator",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT 1",
+				"operator":   "~",
+				"critical":   1,
+			},
+			expectError: true,
+		},
+		{
+			name: "between requires a range",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT 1",
+				"operator":   "between",
+				"critical":   1,
+			},
+			expectError: true,
+		},
+		{
+			name: "missing threshold",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT 1",
+			},
+			expectError: true,
+		},
+		{
+			name: "column assertions",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT name, depth, error_rate FROM queues",
+				"columns": []any{
+					map[string]any{"column": "depth", "operator": "<=", "critical": 100},
+					map[string]any{"column": "error_rate", "operator": "<=", "expected": 0.1},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "column assertion without threshold",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT depth FROM queues",
+				"columns":    []any{map[string]any{"column": "depth"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "column assertions with top-level threshold",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT depth FROM queues",
+				"critical":   100,
+				"columns":    []any{map[string]any{"column": "depth", "critical": 100}},
+			},
+			expectError: true,
+		},
+		{
+			name: "column assertions with row count",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": connection,
+				"query":      "SELECT depth FROM queues",
+				"row_count":  true,
+				"columns":    []any{map[string]any{"column": "depth", "critical": 100}},
+			},
+			expectError: true,
+		},
+		{
+			name: "missing database file",
+			config: map[string]any{
+				"driver":     "sqlite",
+				"connection": map[string]any{"path": filepath.Join(t.TempDir(), "missing.db")},
+				"query":      "SELECT 1",
+				"critical":   1,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSQLCheckerSentinel()
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSQLCheckerSentinel_Check(t *testing.T) {
+	path := newSQLCheckerTestDatabase(t)
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		messageContains string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name: "healthy - single value within threshold",
+			config: map[string]any{
+				"query":    "SELECT count(*) FROM queues",
+				"operator": "==",
+				"critical": 3,
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "query returned 3",
+			expectedMetrics: map[string]float64{"value": 3, "rows": 1},
+		},
+		{
+			name: "unhealthy - single value breaches critical",
+			config: map[string]any{
+				"query":    "SELECT max(depth) FROM queues",
+				"operator": "<",
+				"critical": 100,
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "critical: query returned 250, expected < 100",
+		},
+		{
+			name: "warning - single value breaches warning only",
+			config: map[string]any{
+				"query":    "SELECT depth FROM queues WHERE name = 'sms'",
+				"operator": "<=",
+				"critical": 100,
+				"warning":  50,
+			},
+			expectedStatus:  signal.StatusWarning,
+			messageContains: "warning: query returned 80, expected <= 50",
+		},
+		{
+			name: "healthy - between",
+			config: map[string]any{
+				"query":    "SELECT error_rate FROM queues WHERE name = 'emails'",
+				"operator": "between",
+				"critical": []any{0, 0.05},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "query returned 0.01",
+		},
+		{
+			name: "unhealthy - per-row assertion with labels",
+			config: map[string]any{
+				"query":        "SELECT name, depth, error_rate FROM queues ORDER BY name",
+				"column":       "depth",
+				"label_column": "name",
+				"operator":     "<=",
+				"critical":     100,
+				"warning":      50,
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "critical: 1 of 3 rows outside thresholds (expected <= 100): push=250; warning: 1 of 3 rows outside thresholds (expected <= 50): sms=80",
+			expectedMetrics: map[string]float64{"emails": 12, "push": 250, "sms": 80, "rows": 3},
+		},
+		{
+			name: "healthy - all rows within thresholds",
+			config: map[string]any{
+				"query":    "SELECT name, error_rate FROM queues",
+				"column":   "error_rate",
+				"operator": "!=",
+				"critical": 1,
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "query returned 3 rows, all within thresholds",
+		},
+		{
+			name: "unhealthy - column assertions",
+			config: map[string]any{
+				"query":        "SELECT name, depth, error_rate FROM queues ORDER BY name",
+				"label_column": "name",
+				"columns": []any{
+					map[string]any{"column": "depth", "operator": "<=", "critical": 100, "warning": 50},
+					map[string]any{"column": "error_rate", "operator": "<=", "critical": 0.1},
+				},
+			},
+			expectedStatus: signal.StatusUnhealthy,
+			messageContains: "critical: 1 of 3 rows outside thresholds (expected <= 100): depth[push]=250; " +
+				"warning: 1 of 3 rows outside thresholds (expected <= 50): depth[sms]=80; " +
+				"critical: 1 of 3 rows outside thresholds (expected <= 0.1): error_rate[push]=0.5",
+			expectedMetrics: map[string]float64{
+				"depth[emails]": 12, "depth[push]": 250, "depth[sms]": 80,
+				"error_rate[emails]": 0.01, "error_rate[push]": 0.5, "error_rate[sms]": 0.02,
+				"rows": 3,
+			},
+		},
+		{
+			name: "healthy - column assertions",
+			config: map[string]any{
+				"query": "SELECT depth, error_rate FROM queues WHERE name != 'push'",
+				"columns": []any{
+					map[string]any{"column": "depth", "operator": "<=", "critical": 100},
+					map[string]any{"column": "error_rate", "operator": "<=", "critical": 0.1},
+				},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "query returned 2 rows, all columns within thresholds",
+		},
+		{
+			name: "unhealthy - row count",
+			config: map[string]any{
+				"query":     "SELECT name FROM queues WHERE depth > 50",
+				"row_count": true,
+				"operator":  "==",
+				"critical":  0,
+			},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "critical: query returned 2 rows, expected == 0",
+			expectedMetrics: map[string]float64{"rows": 2},
+		},
+		{
+			name: "healthy - empty result mapped to healthy",
+			config: map[string]any{
+				"query":        "SELECT depth FROM queues WHERE depth > 1000",
+				"critical":     0,
+				"empty_result": "healthy",
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "query returned no rows",
+			expectedMetrics: map[string]float64{"rows": 0},
+		},
+		{
+			name: "unknown - empty result",
+			config: map[string]any{
+				"query":    "SELECT depth FROM queues WHERE depth > 1000",
+				"critical": 0,
+			},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "query returned no rows",
+		},
+		{
+			name: "unknown - non numeric column",
+			config: map[string]any{
+				"query":    "SELECT name FROM queues",
+				"critical": 0,
+			},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "column name is not numeric",
+		},
+		{
+			name: "unknown - missing column",
+			config: map[string]any{
+				"query":    "SELECT depth FROM queues",
+				"column":   "size",
+				"critical": 0,
+			},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "column size not found",
+		},
+		{
+			name: "unknown - invalid query",
+			config: map[string]any{
+				"query":    "SELECT * FROM missing",
+				"critical": 0,
+			},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "failed to execute query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["driver"] = "sqlite"
+			tt.config["connection"] = map[string]any{"path": path}
+			s := NewSQLCheckerSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Contains(t, sig.Message, tt.messageContains)
+			if tt.expectedMetrics != nil {
+				assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
@@ -57,12 +56,14 @@ func (s *SQSCountCheckerSentinel) Configure(config map[string]any) error {
 	}
 	s.awsRegion = awsRegion
 
-	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(s.awsRegion))
+	cfg, endpointURL, err := loadAWSConfig(context.Background(), config)
 	if err != nil {
-		return fmt.Errorf("failed to create AWS config: %v", err)
+		return err
 	}
 
-	s.client = sqs.NewFromConfig(cfg)
+	s.client = sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.BaseEndpoint = baseEndpoint(endpointURL)
+	})
 	return nil
 }
 
@@ -104,12 +105,16 @@ func (s *SQSCountCheckerSentinel) Check(ctx context.Context, alarmID string) (si
 		}, nil
 	}
 
+	metrics := map[string]float64{"queue_depth": float64(messageCount)}
+	labels := map[string]string{"queue_url": s.queueURL}
 	if messageCount <= s.maxMessageCount {
 		return signal.Signal{
 			AlarmID:   alarmID,
 			Status:    signal.StatusHealthy,
 			Timestamp: time.Now(),
 			Message:   fmt.Sprintf("queue has %d messages, which is within the limit of %d", messageCount, s.maxMessageCount),
+			Metrics:   metrics,
+			Labels:    labels,
 		}, nil
 	}
 
@@ -118,5 +123,7 @@ func (s *SQSCountCheckerSentinel) Check(ctx context.Context, alarmID string) (si
 		Status:    signal.StatusUnhealthy,
 		Timestamp: time.Now(),
 		Message:   fmt.Sprintf("queue has %d messages, which exceeds the limit of %d", messageCount, s.maxMessageCount),
+		Metrics:   metrics,
+		Labels:    labels,
 	}, nil
 }
@@ -98,6 +98,7 @@ func TestSQSCountCheckerSentinel_Check(t *testing.T) {
 		messageCount     string
 		maxMessageCount  int64
 		expectedStatus   signal.Status
+		expectedMetrics  map[string]float64
 		attributesResult map[string]string
 		expectError      bool
 	}{
@@ -106,6 +107,7 @@ func TestSQSCountCheckerSentinel_Check(t *testing.T) {
 			messageCount:    "50",
 			maxMessageCount: 100,
 			expectedStatus:  signal.StatusHealthy,
+			expectedMetrics: map[string]float64{"queue_depth": 50},
 			attributesResult: map[string]string{
 				string(types.QueueAttributeNameApproximateNumberOfMessages): "50",
 			},
@@ -115,6 +117,7 @@ func TestSQSCountCheckerSentinel_Check(t *testing.T) {
 			messageCount:    "150",
 			maxMessageCount: 100,
 			expectedStatus:  signal.StatusUnhealthy,
+			expectedMetrics: map[string]float64{"queue_depth": 150},
 			attributesResult: map[string]string{
 				string(types.QueueAttributeNameApproximateNumberOfMessages): "150",
 			},
@@ -153,6 +156,7 @@ func TestSQSCountCheckerSentinel_Check(t *testing.T) {
 			assert.Equal(t, "test-alarm", signal.AlarmID)
 			assert.NotEmpty(t, signal.Message)
 			assert.NotZero(t, signal.Timestamp)
+			assert.Equal(t, tt.expectedMetrics, signal.Metrics)
 		})
 	}
 }
@@ -0,0 +1,244 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// sqsAgeLookback is how far back CloudWatch is searched for the age of the
+// oldest message. SQS publishes the metric every minute while the queue is
+// active.
+const sqsAgeLookback = 15 * time.Minute
+
+type CloudWatchClient interface {
+	GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+type SQSQueueCheckerSentinel struct {
+	queueURL           string
+	deadLetterQueueURL string
+	messages           *thresholds
+	inFlight           *thresholds
+	oldestMessageAge   *thresholds
+	client             SQSClient
+	cloudWatch         CloudWatchClient
+}
+
+func NewSQSQueueCheckerSentinel() sentinel.Sentinel {
+	return &SQSQueueCheckerSentinel{}
+}
+
+func (s *SQSQueueCheckerSentinel) Configure(config map[string]any) error {
+	var err error
+	if s.queueURL, err = getString(config, "queue_url", ""); err != nil {
+		return err
+	}
+	if s.queueURL == "" {
+		return fmt.Errorf("missing required field: queue_url")
+	}
+	if s.deadLetterQueueURL, err = getString(config, "dead_letter_queue_url", ""); err != nil {
+		return err
+	}
+	if s.messages, err = parseNestedThresholds(config, "messages", "<=", false); err != nil {
+		return err
+	}
+	if s.inFlight, err = parseNestedThresholds(config, "in_flight", "<=", false); err != nil {
+		return err
+	}
+	if s.oldestMessageAge, err = parseNestedThresholds(config, "oldest_message_age", "<=", true); err != nil {
+		return err
+	}
+	if s.messages == nil && s.inFlight == nil && s.oldestMessageAge == nil && s.deadLetterQueueURL == "" {
+		return fmt.Errorf("at least one of messages, in_flight, oldest_message_age or dead_letter_queue_url is required")
+	}
+
+	cfg, endpointURL, err := loadAWSConfig(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	cloudWatchEndpointURL, err := getString(config, "cloudwatch_endpoint_url", "")
+	if err != nil {
+		return err
+	}
+	// SQS stand-ins such as ElasticMQ don't serve CloudWatch, and real
+	// CloudWatch has no metrics for their queues.
+	if s.oldestMessageAge != nil && endpointURL != "" && cloudWatchEndpointURL == "" {
+		return fmt.Errorf("oldest_message_age is read from CloudWatch, which endpoint_url doesn't apply to: set cloudwatch_endpoint_url as well, or remove oldest_message_age")
+	}
+	s.client = sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.BaseEndpoint = baseEndpoint(endpointURL)
+	})
+	s.cloudWatch = cloudwatch.NewFromConfig(cfg, func(o *cloudwatch.Options) {
+		o.BaseEndpoint = baseEndpoint(cloudWatchEndpointURL)
+	})
+	return nil
+}
+
+func (s *SQSQueueCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	sig, err := s.check(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	sig.AlarmID = alarmID
+	sig.Timestamp = time.Now()
+	return sig, nil
+}
+
+func (s *SQSQueueCheckerSentinel) check(ctx context.Context) (signal.Signal, error) {
+	worst := thresholdOK
+	var observations, failures []string
+	metrics := make(map[string]float64)
+	evaluate := func(t *thresholds, metric string, value float64, description string) {
+		level := t.evaluate(value)
+		metrics[metric] = value
+		observations = append(observations, description)
+		if level != thresholdOK {
+			worst = max(worst, level)
+			failures = append(failures, fmt.Sprintf("%s: %s, %s", level, description, t.describe(level)))
+		}
+	}
+
+	if s.messages != nil || s.inFlight != nil {
+		attributes, err := s.queueAttributes(ctx, s.queueURL,
+			types.QueueAttributeNameApproximateNumberOfMessages,
+			types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+		)
+		if err != nil {
+			return signal.Signal{}, err
+		}
+		if s.messages != nil {
+			count := attributes[types.QueueAttributeNameApproximateNumberOfMessages]
+			evaluate(s.messages, "queue_depth", float64(count), fmt.Sprintf("queue has %d messages", count))
+		}
+		if s.inFlight != nil {
+			count := attributes[types.QueueAttributeNameApproximateNumberOfMessagesNotVisible]
+			evaluate(s.inFlight, "in_flight", float64(count), fmt.Sprintf("%d messages in flight", count))
+		}
+	}
+
+	if s.oldestMessageAge != nil {
+		age, err := s.oldestMessageAgeSeconds(ctx)
+		if err != nil {
+			return signal.Signal{}, err
+		}
+		evaluate(s.oldestMessageAge, "oldest_message_age_seconds", age, fmt.Sprintf("oldest message is %v old", time.Duration(age)*time.Second))
+	}
+
+	if s.deadLetterQueueURL != "" {
+		attributes, err := s.queueAttributes(ctx, s.deadLetterQueueURL,
+			types.QueueAttributeNameApproximateNumberOfMessages,
+			types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+		)
+		if err != nil {
+			return signal.Signal{}, fmt.Errorf("dead-letter queue: %v", err)
+		}
+		count := attributes[types.QueueAttributeNameApproximateNumberOfMessages] +
+			attributes[types.QueueAttributeNameApproximateNumberOfMessagesNotVisible]
+		metrics["dead_letter_queue_depth"] = float64(count)
+		if count > 0 {
+			worst = thresholdCritical
+			failures = append(failures, fmt.Sprintf("%s: dead-letter queue has %d messages", thresholdCritical, count))
+		}
+		observations = append(observations, fmt.Sprintf("dead-letter queue has %d messages", count))
+	}
+
+	sig := signal.Signal{
+		Status:  worst.status(),
+		Message: strings.Join(failures, "; "),
+		Metrics: metrics,
+		Labels:  map[string]string{"queue_url": s.queueURL},
+	}
+	if worst == thresholdOK {
+		sig.Message = strings.Join(observations, ", ")
+	}
+	return sig, nil
+}
+
+func (s *SQSQueueCheckerSentinel) queueAttributes(ctx context.Context, queueURL string, names ...types.QueueAttributeName) (map[types.QueueAttributeName]int64, error) {
+	result, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: names,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue attributes: %v", err)
+	}
+	values := make(map[types.QueueAttributeName]int64, len(names))
+	for _, name := range names {
+		raw, ok := result.Attributes[string(name)]
+		if !ok {
+			return nil, fmt.Errorf("%s attribute not found in response", name)
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// oldestMessageAgeSeconds reads the latest ApproximateAgeOfOldestMessage
+// datapoint. SQS only publishes the age to CloudWatch, it is not a queue
+// attribute.
+func (s *SQSQueueCheckerSentinel) oldestMessageAgeSeconds(ctx context.Context) (float64, error) {
+	queueName, err := sqsQueueName(s.queueURL)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	result, err := s.cloudWatch.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/SQS"),
+		MetricName: aws.String("ApproximateAgeOfOldestMessage"),
+		Dimensions: []cwtypes.Dimension{{Name: aws.String("QueueName"), Value: aws.String(queueName)}},
+		StartTime:  aws.Time(now.Add(-sqsAgeLookback)),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int32(60),
+		Statistics: []cwtypes.Statistic{cwtypes.StatisticMaximum},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get oldest message age: %v", err)
+	}
+	var latest *cwtypes.Datapoint
+	for i, datapoint := range result.Datapoints {
+		if datapoint.Timestamp == nil || datapoint.Maximum == nil {
+			continue
+		}
+		if latest == nil || datapoint.Timestamp.After(*latest.Timestamp) {
+			latest = &result.Datapoints[i]
+		}
+	}
+	if latest == nil {
+		return 0, fmt.Errorf("no ApproximateAgeOfOldestMessage datapoints in the last %v", sqsAgeLookback)
+	}
+	return *latest.Maximum, nil
+}
+
+func sqsQueueName(queueURL string) (string, error) {
+	parsed, err := url.Parse(queueURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid queue_url: %v", err)
+	}
+	name := path.Base(parsed.Path)
+	if name == "" || name == "/" || name == "." {
+		return "", fmt.Errorf("invalid queue_url, no queue name: %s", queueURL)
+	}
+	return name, nil
+}
@@ -0,0 +1,253 @@
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testQueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/orders"
+	testDLQURL   = "https://sqs.us-east-1.amazonaws.com/123456789012/orders-dlq"
+)
+
+type MockCloudWatchClient struct {
+	GetMetricStatisticsFunc func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+func (m *MockCloudWatchClient) GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	return m.GetMetricStatisticsFunc(ctx, params, optFns...)
+}
+
+func newMockSQSQueues(queues map[string][2]string) *MockSQSClient {
+	return &MockSQSClient{
+		GetQueueAttributesFunc: func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			counts, ok := queues[*params.QueueUrl]
+			if !ok {
+				return nil, fmt.Errorf("AWS.SimpleQueueService.NonExistentQueue")
+			}
+			return &sqs.GetQueueAttributesOutput{
+				Attributes: map[string]string{
+					string(types.QueueAttributeNameApproximateNumberOfMessages):           counts[0],
+					string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible): counts[1],
+				},
+			}, nil
+		},
+	}
+}
+
+func newMockOldestMessageAge(t *testing.T, ages ...float64) *MockCloudWatchClient {
+	return &MockCloudWatchClient{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			assert.Equal(t, "ApproximateAgeOfOldestMessage", *params.MetricName)
+			assert.Equal(t, "orders", *params.Dimensions[0].Value)
+			var datapoints []cwtypes.Datapoint
+			for i, age := range ages {
+				datapoints = append(datapoints, cwtypes.Datapoint{
+					Timestamp: aws.Time(time.Now().Add(-time.Duration(len(ages)-i) * time.Minute)),
+					Maximum:   aws.Float64(age),
+				})
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints}, nil
+		},
+	}
+}
+
+func TestSQSQueueCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"queue_url":               testQueueURL,
+				"aws_region":              "us-east-1",
+				"messages":                map[string]any{"critical": 1000, "warning": 100},
+				"oldest_message_age":      map[string]any{"critical": "15m", "warning": 300},
+				"dead_letter_queue_url":   testDLQURL,
+				"endpoint_url":            "http://localhost:4566",
+				"cloudwatch_endpoint_url": "http://localhost:4566",
+				"aws_access_key_id":       "x",
+				"aws_secret_access_key":   "x",
+			},
+			expectError: false,
+		},
+		{
+			name: "oldest message age against an SQS stand-in",
+			config: map[string]any{
+				"queue_url":          testQueueURL,
+				"aws_region":         "us-east-1",
+				"oldest_message_age": map[string]any{"critical": "15m"},
+				"endpoint_url":       "http://localhost:9324",
+			},
+			expectError: true,
+		},
+		{
+			name: "no checks",
+			config: map[string]any{
+				"queue_url":  testQueueURL,
+				"aws_region": "us-east-1",
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid age threshold",
+			config: map[string]any{
+				"queue_url":          testQueueURL,
+				"aws_region":         "us-east-1",
+				"oldest_message_age": map[string]any{"critical": "15 minutes"},
+			},
+			expectError: true,
+		},
+		{
+			name: "access key without secret",
+			config: map[string]any{
+				"queue_url":         testQueueURL,
+				"aws_region":        "us-east-1",
+				"in_flight":         map[string]any{"critical": 10},
+				"aws_access_key_id": "x",
+			},
+			expectError: true,
+		},
+		{
+			name: "missing region",
+			config: map[string]any{
+				"queue_url": testQueueURL,
+				"in_flight": map[string]any{"critical": 10},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSQSQueueCheckerSentinel().Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSQSQueueCheckerSentinel_Endpoints(t *testing.T) {
+	s := NewSQSQueueCheckerSentinel().(*SQSQueueCheckerSentinel)
+	require.NoError(t, s.Configure(map[string]any{
+		"queue_url":             testQueueURL,
+		"aws_region":            "us-east-1",
+		"messages":              map[string]any{"critical": 1000},
+		"endpoint_url":          "http://localhost:9324",
+		"aws_access_key_id":     "x",
+		"aws_secret_access_key": "x",
+	}))
+	assert.Equal(t, aws.String("http://localhost:9324"), s.client.(*sqs.Client).Options().BaseEndpoint)
+	assert.Nil(t, s.cloudWatch.(*cloudwatch.Client).Options().BaseEndpoint)
+
+	require.NoError(t, s.Configure(map[string]any{
+		"queue_url":               testQueueURL,
+		"aws_region":              "us-east-1",
+		"oldest_message_age":      map[string]any{"critical": "15m"},
+		"endpoint_url":            "http://localhost:4566",
+		"cloudwatch_endpoint_url": "http://localhost:4567",
+		"aws_access_key_id":       "x",
+		"aws_secret_access_key":   "x",
+	}))
+	assert.Equal(t, aws.String("http://localhost:4566"), s.client.(*sqs.Client).Options().BaseEndpoint)
+	assert.Equal(t, aws.String("http://localhost:4567"), s.cloudWatch.(*cloudwatch.Client).Options().BaseEndpoint)
+}
+
+func TestSQSQueueCheckerSentinel_Check(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]any
+		queues          map[string][2]string
+		ages            []float64
+		expectedStatus  signal.Status
+		expectedMessage string
+		expectedMetrics map[string]float64
+	}{
+		{
+			name: "healthy",
+			config: map[string]any{
+				"messages":              map[string]any{"critical": 1000},
+				"in_flight":             map[string]any{"critical": 100},
+				"oldest_message_age":    map[string]any{"critical": "10m"},
+				"dead_letter_queue_url": testDLQURL,
+			},
+			queues:          map[string][2]string{testQueueURL: {"40", "5"}, testDLQURL: {"0", "0"}},
+			ages:            []float64{900, 42},
+			expectedStatus:  signal.StatusHealthy,
+			expectedMessage: "queue has 40 messages, 5 messages in flight, oldest message is 42s old, dead-letter queue has 0 messages",
+			expectedMetrics: map[string]float64{"queue_depth": 40, "in_flight": 5, "oldest_message_age_seconds": 42, "dead_letter_queue_depth": 0},
+		},
+		{
+			name: "stuck consumer",
+			config: map[string]any{
+				"messages":           map[string]any{"critical": 1000, "warning": 20},
+				"oldest_message_age": map[string]any{"critical": "10m", "warning": "5m"},
+			},
+			queues:         map[string][2]string{testQueueURL: {"40", "0"}},
+			ages:           []float64{600, 1200},
+			expectedStatus: signal.StatusUnhealthy,
+			expectedMessage: "warning: queue has 40 messages, expected <= 20; " +
+				"critical: oldest message is 20m0s old, expected <= 600",
+			expectedMetrics: map[string]float64{"queue_depth": 40, "oldest_message_age_seconds": 1200},
+		},
+		{
+			name:            "messages in dead-letter queue",
+			config:          map[string]any{"dead_letter_queue_url": testDLQURL},
+			queues:          map[string][2]string{testDLQURL: {"2", "1"}},
+			expectedStatus:  signal.StatusUnhealthy,
+			expectedMessage: "critical: dead-letter queue has 3 messages",
+			expectedMetrics: map[string]float64{"dead_letter_queue_depth": 3},
+		},
+		{
+			name:            "no age datapoints",
+			config:          map[string]any{"oldest_message_age": map[string]any{"critical": "10m"}},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "no ApproximateAgeOfOldestMessage datapoints in the last 15m0s",
+		},
+		{
+			name:            "missing dead-letter queue",
+			config:          map[string]any{"dead_letter_queue_url": testDLQURL},
+			queues:          map[string][2]string{},
+			expectedStatus:  signal.StatusUnknown,
+			expectedMessage: "dead-letter queue: failed to get queue attributes: AWS.SimpleQueueService.NonExistentQueue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["queue_url"] = testQueueURL
+			tt.config["aws_region"] = "us-east-1"
+			s := NewSQSQueueCheckerSentinel().(*SQSQueueCheckerSentinel)
+			require.NoError(t, s.Configure(tt.config))
+			s.client = newMockSQSQueues(tt.queues)
+			s.cloudWatch = newMockOldestMessageAge(t, tt.ages...)
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Equal(t, tt.expectedStatus, sig.Status)
+			assert.Equal(t, tt.expectedMessage, sig.Message)
+			assert.Equal(t, tt.expectedMetrics, sig.Metrics)
+			if tt.expectedMetrics != nil {
+				assert.Equal(t, map[string]string{"queue_url": testQueueURL}, sig.Labels)
+			}
+			assert.NotZero(t, sig.Timestamp)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	DefaultSSHCommandTimeout = 30 * time.Second
+	maxCommandOutputSize     = 64 * 1024
+)
+
+type SSHCommandSentinel struct {
+	connection       connections.TunnelConfig
+	command          string
+	timeout          time.Duration
+	expectedExitCode int
+	stdoutMatch      *regexp.Regexp
+	valueRegex       *regexp.Regexp
+	thresholds       *thresholds
+}
+
+func NewSSHCommandSentinel() sentinel.Sentinel {
+	return &SSHCommandSentinel{}
+}
+
+func (s *SSHCommandSentinel) Configure(config map[string]any) error {
+	for _, field := range []string{"connection", "command"} {
+		if _, ok := config[field]; !ok {
+			return fmt.Errorf("missing required field: %s", field)
+		}
+	}
+	connConfig, ok := config["connection"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("connection config must be a map")
+	}
+	tunnelConfig, err := connections.NewTunnelConfig(connConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create SSH connection config: %v", err)
+	}
+	s.connection = *tunnelConfig
+
+	if s.command, err = getString(config, "command", ""); err != nil {
+		return err
+	}
+	if s.command == "" {
+		return fmt.Errorf("missing required field: command")
+	}
+	if s.timeout, err = getDuration(config, "timeout", DefaultSSHCommandTimeout); err != nil {
+		return err
+	}
+	exitCode, err := getInt64(config, "expected_exit_code", 0)
+	if err != nil {
+		return err
+	}
+	s.expectedExitCode = int(exitCode)
+
+	stdoutRegex, err := getString(config, "stdout_regex", "")
+	if err != nil {
+		return err
+	}
+	if stdoutRegex != "" {
+		if s.stdoutMatch, err = regexp.Compile(stdoutRegex); err != nil {
+			return fmt.Errorf("invalid `stdout_regex`: %v", err)
+		}
+	}
+
+	if _, ok := config["critical"]; ok {
+		t, err := parseThresholds(config, "critical", "<=")
+		if err != nil {
+			return err
+		}
+		s.thresholds = &t
+		valueRegex, err := getString(config, "value_regex", "")
+		if err != nil {
+			return err
+		}
+		if valueRegex != "" {
+			if s.valueRegex, err = regexp.Compile(valueRegex); err != nil {
+				return fmt.Errorf("invalid `value_regex`: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SSHCommandSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	stdout, stderr, exitCode, err := s.run(ctx)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+
+	if exitCode != s.expectedExitCode {
+		message := fmt.Sprintf("command exited with status %d, expected %d", exitCode, s.expectedExitCode)
+		if line := firstLine(stderr); line != "" {
+			message += ": " + line
+		} else if line := firstLine(stdout); line != "" {
+			message += ": " + line
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   message,
+		}, nil
+	}
+
+	if s.stdoutMatch != nil && !s.stdoutMatch.MatchString(stdout) {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("expected output to match %s, got %s", s.stdoutMatch.String(), firstLine(stdout)),
+		}, nil
+	}
+
+	if s.thresholds == nil {
+		message := fmt.Sprintf("command exited with status %d", exitCode)
+		if line := firstLine(stdout); line != "" {
+			message += ": " + line
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusHealthy,
+			Timestamp: time.Now(),
+			Message:   message,
+		}, nil
+	}
+
+	value, err := s.parseValue(stdout)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   err.Error(),
+		}, nil
+	}
+	level := s.thresholds.evaluate(value)
+	message := fmt.Sprintf("command returned %s", formatNumber(value))
+	if level != thresholdOK {
+		message = fmt.Sprintf("%s: command returned %s, %s", level, formatNumber(value), s.thresholds.describe(level))
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    level.status(),
+		Timestamp: time.Now(),
+		Message:   message,
+	}, nil
+}
+
+func (s *SSHCommandSentinel) run(ctx context.Context) (string, string, int, error) {
+	tunnel, err := connections.OpenTunnel(s.connection)
+	if err != nil {
+		var hostKeyErr *connections.HostKeyError
+		if errors.As(err, &hostKeyErr) {
+			return "", "", 0, hostKeyErr
+		}
+		return "", "", 0, fmt.Errorf("failed to connect to %s: %v", s.connection.Host, err)
+	}
+	defer tunnel.Close()
+
+	session, err := tunnel.NewSession()
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to open SSH session: %v", err)
+	}
+	defer session.Close()
+
+	stdout := &limitedBuffer{limit: maxCommandOutputSize}
+	stderr := &limitedBuffer{limit: maxCommandOutputSize}
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(s.command)
+	}()
+
+	select {
+	case <-ctx.Done():
+		session.Close()
+		return "", "", 0, fmt.Errorf("command timed out after %v", s.timeout)
+	case err = <-done:
+	}
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		return stdout.String(), stderr.String(), 0, nil
+	case errors.As(err, &exitErr):
+		return stdout.String(), stderr.String(), exitErr.ExitStatus(), nil
+	default:
+		return "", "", 0, fmt.Errorf("failed to run command: %v", err)
+	}
+}
+
+func (s *SSHCommandSentinel) parseValue(stdout string) (float64, error) {
+	text := strings.TrimSpace(stdout)
+	if s.valueRegex != nil {
+		match := s.valueRegex.FindStringSubmatch(stdout)
+		if match == nil {
+			return 0, fmt.Errorf("output does not match %s: %s", s.valueRegex.String(), firstLine(stdout))
+		}
+		text = match[0]
+		if len(match) > 1 {
+			text = match[1]
+		}
+	}
+	value, ok := toFloat64(text)
+	if !ok {
+		return 0, fmt.Errorf("output is not numeric: %s", firstLine(text))
+	}
+	return value, nil
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+	return text
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a chatty command can't exhaust the worker's memory.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
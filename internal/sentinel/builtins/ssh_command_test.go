@@ -0,0 +1,309 @@
+package builtins
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+type fakeSSHCommand struct {
+	stdout   string
+	stderr   string
+	exitCode uint32
+	delay    time.Duration
+}
+
+// startSSHCommandServer runs an SSH server that answers exec requests from
+// commands and serves the local file system over SFTP, and returns a
+// connection config for it.
+func startSSHCommandServer(t *testing.T, commands map[string]fakeSSHCommand) (map[string]any, ssh.PublicKey) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "monitor" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials")
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHCommands(conn, serverConfig, commands)
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return map[string]any{
+		"host":                  host,
+		"port":                  portNumber,
+		"user":                  "monitor",
+		"password":              "secret",
+		"host_key_fingerprints": []any{ssh.FingerprintSHA256(signer.PublicKey())},
+	}, signer.PublicKey()
+}
+
+func serveSSHCommands(conn net.Conn, config *ssh.ServerConfig, commands map[string]fakeSSHCommand) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type == "subsystem" {
+					var payload struct{ Name string }
+					if err := ssh.Unmarshal(req.Payload, &payload); err != nil || payload.Name != "sftp" {
+						req.Reply(false, nil)
+						continue
+					}
+					req.Reply(true, nil)
+					server, err := sftp.NewServer(channel)
+					if err != nil {
+						return
+					}
+					server.Serve()
+					return
+				}
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				var payload struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+					req.Reply(false, nil)
+					return
+				}
+				req.Reply(true, nil)
+				command, ok := commands[payload.Command]
+				if !ok {
+					command = fakeSSHCommand{stderr: "command not found", exitCode: 127}
+				}
+				time.Sleep(command.delay)
+				channel.Write([]byte(command.stdout))
+				channel.Stderr().Write([]byte(command.stderr))
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{command.exitCode}))
+				return
+			}
+		}()
+	}
+}
+
+func TestSSHCommandSentinel_Configure(t *testing.T) {
+	connection := map[string]any{"host": "vm.example.com", "port": 22, "user": "monitor", "password": "secret"}
+
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name: "valid configuration",
+			config: map[string]any{
+				"connection": connection,
+				"command":    "df --output=pcent / | tail -1",
+				"operator":   "<",
+				"critical":   90,
+				"warning":    80,
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing command",
+			config:      map[string]any{"connection": connection},
+			expectError: true,
+		},
+		{
+			name:        "missing credentials",
+			config:      map[string]any{"connection": map[string]any{"host": "vm", "port": 22, "user": "monitor"}, "command": "true"},
+			expectError: true,
+		},
+		{
+			name:        "invalid stdout regex",
+			config:      map[string]any{"connection": connection, "command": "true", "stdout_regex": "("},
+			expectError: true,
+		},
+		{
+			name:        "invalid value regex",
+			config:      map[string]any{"connection": connection, "command": "true", "critical": 1, "value_regex": "("},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSSHCommandSentinel()
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSSHCommandSentinel_Check(t *testing.T) {
+	connection, hostKey := startSSHCommandServer(t, map[string]fakeSSHCommand{
+		"disk":      {stdout: " 87%\n"},
+		"procs":     {stdout: "workers: 3\n"},
+		"backup":    {stdout: "backup finished OK\n"},
+		"failing":   {stdout: "partial output\n", stderr: "permission denied\n", exitCode: 2},
+		"garbage":   {stdout: "not a number\n"},
+		"sleepy":    {stdout: "done\n", delay: 500 * time.Millisecond},
+		"nagios-ok": {stdout: "OK - all good\nmore details\n"},
+	})
+
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedStatus  signal.Status
+		messageContains string
+	}{
+		{
+			name:            "healthy - exit code",
+			config:          map[string]any{"command": "nagios-ok"},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "command exited with status 0: OK - all good",
+		},
+		{
+			name:            "unhealthy - exit code",
+			config:          map[string]any{"command": "failing"},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "command exited with status 2, expected 0: permission denied",
+		},
+		{
+			name:            "healthy - expected non zero exit code",
+			config:          map[string]any{"command": "failing", "expected_exit_code": 2},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "command exited with status 2",
+		},
+		{
+			name:            "healthy - stdout regex",
+			config:          map[string]any{"command": "backup", "stdout_regex": "finished OK"},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "backup finished OK",
+		},
+		{
+			name:            "unhealthy - stdout regex",
+			config:          map[string]any{"command": "backup", "stdout_regex": "^ERROR"},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "expected output to match ^ERROR",
+		},
+		{
+			name:            "unhealthy - value breaches critical",
+			config:          map[string]any{"command": "disk", "value_regex": `(\d+)%`, "operator": "<", "critical": 85, "warning": 75},
+			expectedStatus:  signal.StatusUnhealthy,
+			messageContains: "critical: command returned 87, expected < 85",
+		},
+		{
+			name:            "warning - value breaches warning",
+			config:          map[string]any{"command": "disk", "value_regex": `(\d+)%`, "operator": "<", "critical": 95, "warning": 80},
+			expectedStatus:  signal.StatusWarning,
+			messageContains: "warning: command returned 87, expected < 80",
+		},
+		{
+			name:            "healthy - value between",
+			config:          map[string]any{"command": "procs", "value_regex": `workers: (\d+)`, "operator": "between", "critical": []any{1, 5}},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "command returned 3",
+		},
+		{
+			name:            "unknown - value not numeric",
+			config:          map[string]any{"command": "garbage", "critical": 1},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "output is not numeric: not a number",
+		},
+		{
+			name:            "unknown - timeout",
+			config:          map[string]any{"command": "sleepy", "timeout": "100ms"},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "command timed out after 100ms",
+		},
+		{
+			name: "unknown - host key mismatch",
+			config: map[string]any{
+				"command": "backup",
+				"connection": map[string]any{
+					"host":                  connection["host"],
+					"port":                  connection["port"],
+					"user":                  "monitor",
+					"password":              "secret",
+					"host_key_fingerprints": []any{"SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"},
+				},
+			},
+			expectedStatus:  signal.StatusUnknown,
+			messageContains: "host key verification failed",
+		},
+		{
+			name: "healthy - pinned host key",
+			config: map[string]any{
+				"command": "backup",
+				"connection": map[string]any{
+					"host":                  connection["host"],
+					"port":                  connection["port"],
+					"user":                  "monitor",
+					"password":              "secret",
+					"host_key_fingerprints": []any{ssh.FingerprintSHA256(hostKey)},
+				},
+			},
+			expectedStatus:  signal.StatusHealthy,
+			messageContains: "backup finished OK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.config["connection"]; !ok {
+				tt.config["connection"] = connection
+			}
+			s := NewSSHCommandSentinel()
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Contains(t, sig.Message, tt.messageContains)
+		})
+	}
+}
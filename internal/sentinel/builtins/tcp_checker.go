@@ -0,0 +1,202 @@
+package builtins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel/connections"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const (
+	DefaultTCPTimeout = 10 * time.Second
+	maxBannerSize     = 4096
+)
+
+type TCPCheckerSentinel struct {
+	host          string
+	port          int
+	payload       string
+	expectedMatch *regexp.Regexp
+	timeout       time.Duration
+	tunnel        *connections.TunnelConfig
+}
+
+func NewTCPCheckerSentinel() sentinel.Sentinel {
+	return &TCPCheckerSentinel{}
+}
+
+func (s *TCPCheckerSentinel) Configure(config map[string]any) error {
+	for _, field := range []string{"host", "port"} {
+		if _, ok := config[field]; !ok {
+			return fmt.Errorf("missing required field: %s", field)
+		}
+	}
+	var err error
+	s.host, err = getString(config, "host", "")
+	if err != nil {
+		return err
+	}
+	port, err := getInt64(config, "port", 0)
+	if err != nil {
+		return err
+	}
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	s.port = int(port)
+
+	s.payload, err = getString(config, "send", "")
+	if err != nil {
+		return err
+	}
+	expect, err := getString(config, "expect", "")
+	if err != nil {
+		return err
+	}
+	if expect != "" {
+		s.expectedMatch, err = regexp.Compile(expect)
+		if err != nil {
+			return fmt.Errorf("invalid `expect` regex: %v", err)
+		}
+	}
+
+	s.timeout, err = getDuration(config, "timeout", DefaultTCPTimeout)
+	if err != nil {
+		return err
+	}
+
+	if tunnelConfig, ok := config["tunnel"].(map[string]any); ok {
+		s.tunnel, err = connections.NewTunnelConfig(tunnelConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create tunnel config: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *TCPCheckerSentinel) dial(ctx context.Context, addr string) (net.Conn, func(), error) {
+	if s.tunnel != nil && s.tunnel.Host != "" {
+		tunnel, err := connections.OpenTunnel(*s.tunnel)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn, err := tunnel.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			tunnel.Close()
+			return nil, nil, err
+		}
+		return conn, func() { tunnel.Close() }, nil
+	}
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() {}, nil
+}
+
+func (s *TCPCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	startTime := time.Now()
+	conn, closeTunnel, err := s.dial(ctx, addr)
+	var hostKeyErr *connections.HostKeyError
+	if errors.As(err, &hostKeyErr) {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   hostKeyErr.Error(),
+		}, nil
+	}
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to connect to %s: %v", addr, err),
+		}, nil
+	}
+	connectTime := time.Since(startTime)
+	metrics := map[string]float64{"latency_ms": float64(connectTime.Milliseconds())}
+	defer closeTunnel()
+	defer conn.Close()
+
+	if s.payload == "" && s.expectedMatch == nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusHealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("connected to %s in %vms", addr, connectTime.Milliseconds()),
+			Metrics:   metrics,
+		}, nil
+	}
+
+	conn.SetDeadline(time.Now().Add(s.timeout))
+	if s.payload != "" {
+		if _, err := conn.Write([]byte(s.payload)); err != nil {
+			return signal.Signal{
+				AlarmID:   alarmID,
+				Status:    signal.StatusUnhealthy,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("failed to send payload to %s: %v", addr, err),
+				Metrics:   metrics,
+			}, nil
+		}
+	}
+
+	if s.expectedMatch == nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusHealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("connected to %s in %vms", addr, connectTime.Milliseconds()),
+			Metrics:   metrics,
+		}, nil
+	}
+
+	banner, err := s.readUntilMatch(conn)
+	if !s.expectedMatch.Match(banner) {
+		message := fmt.Sprintf("response from %s did not match %q, got %q", addr, s.expectedMatch.String(), banner)
+		if err != nil {
+			message = fmt.Sprintf("%s: %v", message, err)
+		}
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   message,
+			Metrics:   metrics,
+		}, nil
+	}
+
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusHealthy,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("connected to %s in %vms, response matched %q", addr, connectTime.Milliseconds(), s.expectedMatch.String()),
+		Metrics:   metrics,
+	}, nil
+}
+
+func (s *TCPCheckerSentinel) readUntilMatch(conn net.Conn) ([]byte, error) {
+	banner := make([]byte, 0, maxBannerSize)
+	buf := make([]byte, 512)
+	for len(banner) < maxBannerSize {
+		n, err := conn.Read(buf)
+		banner = append(banner, buf[:n]...)
+		if s.expectedMatch.Match(banner) {
+			return banner, nil
+		}
+		if err != nil {
+			return banner, err
+		}
+	}
+	return banner, nil
+}
@@ -0,0 +1,146 @@
+package builtins
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTCPServer(t *testing.T, handle func(conn net.Conn)) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestTCPCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name:        "valid configuration",
+			config:      map[string]any{"host": "localhost", "port": 6379, "send": "PING\r\n", "expect": "^\\+PONG"},
+			expectError: false,
+		},
+		{
+			name:        "missing port",
+			config:      map[string]any{"host": "localhost"},
+			expectError: true,
+		},
+		{
+			name:        "invalid port",
+			config:      map[string]any{"host": "localhost", "port": 70000},
+			expectError: true,
+		},
+		{
+			name:        "invalid regex",
+			config:      map[string]any{"host": "localhost", "port": 25, "expect": "(unclosed"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TCPCheckerSentinel{}
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTCPCheckerSentinel_Check(t *testing.T) {
+	echoPort := startTCPServer(t, func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		if line == "PING\r\n" {
+			conn.Write([]byte("+PONG\r\n"))
+		} else {
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+	})
+	bannerPort := startTCPServer(t, func(conn net.Conn) {
+		conn.Write([]byte("220 mail.example.com ESMTP ready\r\n"))
+	})
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := closedListener.Addr().(*net.TCPAddr).Port
+	closedListener.Close()
+
+	tests := []struct {
+		name           string
+		config         map[string]any
+		expectedStatus signal.Status
+		unreachable    bool
+	}{
+		{
+			name:           "healthy - connect only",
+			config:         map[string]any{"host": "127.0.0.1", "port": bannerPort},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - banner matches",
+			config:         map[string]any{"host": "127.0.0.1", "port": bannerPort, "expect": "^220 "},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "healthy - payload response matches",
+			config:         map[string]any{"host": "127.0.0.1", "port": echoPort, "send": "PING\r\n", "expect": "^\\+PONG"},
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - response does not match",
+			config:         map[string]any{"host": "127.0.0.1", "port": echoPort, "send": "HELLO\r\n", "expect": "^\\+PONG"},
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "unhealthy - connection refused",
+			config:         map[string]any{"host": "127.0.0.1", "port": closedPort},
+			expectedStatus: signal.StatusUnhealthy,
+			unreachable:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TCPCheckerSentinel{}
+			require.NoError(t, s.Configure(tt.config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.NotEmpty(t, sig.Message)
+			if tt.unreachable {
+				assert.Empty(t, sig.Metrics)
+			} else {
+				assert.Contains(t, sig.Metrics, "latency_ms")
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package builtins
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// maxReportedValues caps how many offending values are listed in a signal message.
+const maxReportedValues = 5
+
+var thresholdOperators = []string{"<", "<=", ">", ">=", "==", "!=", "between"}
+
+type thresholdLevel int
+
+const (
+	thresholdOK thresholdLevel = iota
+	thresholdWarning
+	thresholdCritical
+)
+
+// thresholds describe the condition a value must satisfy to be healthy, e.g.
+// `<= 1000`. The critical bound is required, the warning bound is an optional
+// stricter condition using the same operator.
+type thresholds struct {
+	operator string
+	critical []float64
+	warning  []float64
+}
+
+func parseThresholds(config map[string]any, criticalField string, defaultOperator string) (thresholds, error) {
+	var t thresholds
+	var err error
+	if t.operator, err = getString(config, "operator", defaultOperator); err != nil {
+		return t, err
+	}
+	if !slices.Contains(thresholdOperators, t.operator) {
+		return t, fmt.Errorf("unsupported operator %s, expected one of %v", t.operator, thresholdOperators)
+	}
+	if _, ok := config[criticalField]; !ok {
+		return t, fmt.Errorf("missing required field: %s", criticalField)
+	}
+	if t.critical, err = t.parseBound(config, criticalField); err != nil {
+		return t, err
+	}
+	if _, ok := config["warning"]; ok {
+		if t.warning, err = t.parseBound(config, "warning"); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
+// parseNestedThresholds reads an optional map of thresholds, e.g.
+// `messages: {critical: 1000, warning: 500}`. Duration thresholds accept Go
+// duration strings and are compared in seconds.
+func parseNestedThresholds(config map[string]any, field string, defaultOperator string, durations bool) (*thresholds, error) {
+	raw, ok := config[field]
+	if !ok {
+		return nil, nil
+	}
+	nested, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map", field)
+	}
+	if durations {
+		converted := make(map[string]any, len(nested))
+		for name, value := range nested {
+			converted[name] = value
+		}
+		for _, name := range []string{"critical", "warning"} {
+			if _, ok := nested[name].(string); !ok {
+				continue
+			}
+			d, err := getDuration(nested, name, 0)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", field, err)
+			}
+			converted[name] = d.Seconds()
+		}
+		nested = converted
+	}
+	t, err := parseThresholds(nested, "critical", defaultOperator)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", field, err)
+	}
+	return &t, nil
+}
+
+func (t thresholds) parseBound(config map[string]any, field string) ([]float64, error) {
+	value := config[field]
+	if t.operator != "between" {
+		n, ok := toFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("can't convert `%s` to number: %v", field, value)
+		}
+		return []float64{n}, nil
+	}
+	values, ok := value.([]any)
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("`%s` must be a [min, max] list when using the between operator", field)
+	}
+	bound := make([]float64, 2)
+	for i, v := range values {
+		n, ok := toFloat64(v)
+		if !ok {
+			return nil, fmt.Errorf("can't convert `%s` to number: %v", field, v)
+		}
+		bound[i] = n
+	}
+	if bound[0] > bound[1] {
+		return nil, fmt.Errorf("`%s` min must not be greater than max", field)
+	}
+	return bound, nil
+}
+
+func (t thresholds) satisfies(value float64, bound []float64) bool {
+	switch t.operator {
+	case "<":
+		return value < bound[0]
+	case "<=":
+		return value <= bound[0]
+	case ">":
+		return value > bound[0]
+	case ">=":
+		return value >= bound[0]
+	case "==":
+		return value == bound[0]
+	case "!=":
+		return value != bound[0]
+	case "between":
+		return value >= bound[0] && value <= bound[1]
+	}
+	return false
+}
+
+func (t thresholds) evaluate(value float64) thresholdLevel {
+	if !t.satisfies(value, t.critical) {
+		return thresholdCritical
+	}
+	if t.warning != nil && !t.satisfies(value, t.warning) {
+		return thresholdWarning
+	}
+	return thresholdOK
+}
+
+// labeledValue is one of several values checked against the same thresholds,
+// e.g. a query row or a time series.
+type labeledValue struct {
+	label string
+	value float64
+	level thresholdLevel
+}
+
+// evaluateAll sets the level of every value and returns the worst one.
+func (t thresholds) evaluateAll(values []labeledValue) thresholdLevel {
+	worst := thresholdOK
+	for i := range values {
+		values[i].level = t.evaluate(values[i].value)
+		worst = max(worst, values[i].level)
+	}
+	return worst
+}
+
+// describeResults describes a query's results. A single unlabeled value is
+// reported on its own, e.g. "warning: query returned 42, expected <= 10", and
+// anything else is summarized by describeAll.
+func (t thresholds) describeResults(values []labeledValue, worst thresholdLevel, labeled bool, noun string) string {
+	if len(values) == 1 && !labeled {
+		v := values[0]
+		if v.level == thresholdOK {
+			return fmt.Sprintf("query returned %s", formatNumber(v.value))
+		}
+		return fmt.Sprintf("%s: query returned %s, %s", v.level, formatNumber(v.value), t.describe(v.level))
+	}
+	return t.describeAll(values, worst, noun)
+}
+
+// resultMetrics records query results as signal metrics: a single unlabeled
+// value as "value" and anything else under its label, e.g. "push".
+func resultMetrics(values []labeledValue, labeled bool) map[string]float64 {
+	if len(values) == 1 && !labeled {
+		return map[string]float64{"value": values[0].value}
+	}
+	metrics := make(map[string]float64, len(values))
+	for _, v := range values {
+		metrics[v.label] = v.value
+	}
+	return metrics
+}
+
+// describeAll summarizes evaluated values, listing the offending ones per
+// level, e.g. "critical: 1 of 3 rows outside thresholds (expected <= 100): push=250".
+func (t thresholds) describeAll(values []labeledValue, worst thresholdLevel, noun string) string {
+	if worst == thresholdOK {
+		return fmt.Sprintf("query returned %d %s, all within thresholds", len(values), noun)
+	}
+	var parts []string
+	for _, level := range []thresholdLevel{thresholdCritical, thresholdWarning} {
+		var offending []string
+		for _, v := range values {
+			if v.level == level {
+				offending = append(offending, fmt.Sprintf("%s=%s", v.label, formatNumber(v.value)))
+			}
+		}
+		if len(offending) == 0 {
+			continue
+		}
+		count := len(offending)
+		if count > maxReportedValues {
+			offending = append(offending[:maxReportedValues], fmt.Sprintf("and %d more", count-maxReportedValues))
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d of %d %s outside thresholds (%s): %s",
+			level, count, len(values), noun, t.describe(level), strings.Join(offending, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (t thresholds) describe(level thresholdLevel) string {
+	bound := t.critical
+	if level == thresholdWarning {
+		bound = t.warning
+	}
+	if t.operator == "between" {
+		return fmt.Sprintf("expected between %s and %s", formatNumber(bound[0]), formatNumber(bound[1]))
+	}
+	return fmt.Sprintf("expected %s %s", t.operator, formatNumber(bound[0]))
+}
+
+// status maps a level to a signal status.
+func (level thresholdLevel) status() signal.Status {
+	switch level {
+	case thresholdCritical:
+		return signal.StatusUnhealthy
+	case thresholdWarning:
+		return signal.StatusWarning
+	default:
+		return signal.StatusHealthy
+	}
+}
+
+func (level thresholdLevel) String() string {
+	switch level {
+	case thresholdWarning:
+		return "warning"
+	case thresholdCritical:
+		return "critical"
+	default:
+		return "ok"
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,164 @@
+package builtins
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const (
+	DefaultTLSPort        = 443
+	DefaultMinDaysValid   = 14
+	DefaultTLSDialTimeout = 10 * time.Second
+)
+
+type TLSCertCheckerSentinel struct {
+	host         string
+	port         int
+	serverName   string
+	minDaysValid int
+	timeout      time.Duration
+	rootCAs      *x509.CertPool
+}
+
+func NewTLSCertCheckerSentinel() sentinel.Sentinel {
+	return &TLSCertCheckerSentinel{}
+}
+
+func (s *TLSCertCheckerSentinel) Configure(config map[string]any) error {
+	if _, ok := config["host"]; !ok {
+		return fmt.Errorf("missing required field: host")
+	}
+	host, err := getString(config, "host", "")
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		return fmt.Errorf("missing required field: host")
+	}
+	s.host = host
+
+	port, err := getInt64(config, "port", DefaultTLSPort)
+	if err != nil {
+		return err
+	}
+	s.port = int(port)
+
+	s.serverName, err = getString(config, "server_name", s.host)
+	if err != nil {
+		return err
+	}
+
+	minDaysValid, err := getInt64(config, "min_days_valid", DefaultMinDaysValid)
+	if err != nil {
+		return err
+	}
+	s.minDaysValid = int(minDaysValid)
+
+	s.timeout, err = getDuration(config, "timeout", DefaultTLSDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	caFile, err := getString(config, "ca_file", "")
+	if err != nil {
+		return err
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read ca_file: %v", err)
+		}
+		s.rootCAs = x509.NewCertPool()
+		if !s.rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in ca_file %s", caFile)
+		}
+	}
+	return nil
+}
+
+func (s *TLSCertCheckerSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: s.timeout},
+		Config: &tls.Config{
+			ServerName: s.serverName,
+			// the chain is verified below so that verification failures
+			// can be reported alongside the certificate details
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("failed to establish TLS connection to %s: %v", addr, err),
+		}, nil
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("%s presented no certificates", addr),
+		}, nil
+	}
+	leaf := certs[0]
+	details := fmt.Sprintf("expires %s, issued by %s", leaf.NotAfter.UTC().Format(time.DateOnly), leaf.Issuer.CommonName)
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         s.rootCAs,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("certificate chain verification failed: %v (%s)", err, details),
+		}, nil
+	}
+
+	if err := leaf.VerifyHostname(s.serverName); err != nil {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("certificate does not match %s: %v (%s)", s.serverName, err, details),
+		}, nil
+	}
+
+	daysLeft := int(time.Until(leaf.NotAfter).Hours() / 24)
+	if daysLeft < s.minDaysValid {
+		return signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnhealthy,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("certificate expires in %d days, minimum is %d (%s)", daysLeft, s.minDaysValid, details),
+		}, nil
+	}
+
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusHealthy,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("certificate valid for %d more days (%s)", daysLeft, details),
+	}, nil
+}
@@ -0,0 +1,197 @@
+package builtins
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pemEncodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func startTLSServer(t *testing.T, notAfter time.Time, dnsNames []string) (host string, port int, caFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mirante test"},
+		Issuer:                pkix.Name{CommonName: "mirante test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              dnsNames,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile = filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pemEncodeCertificate(der), 0644))
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	host, portStr, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	port, err = strconv.Atoi(portStr)
+	require.NoError(t, err)
+	return host, port, caFile
+}
+
+func TestTLSCertCheckerSentinel_Configure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectError bool
+	}{
+		{
+			name:        "valid configuration",
+			config:      map[string]any{"host": "example.com", "min_days_valid": 30},
+			expectError: false,
+		},
+		{
+			name:        "missing host",
+			config:      map[string]any{"port": 443},
+			expectError: true,
+		},
+		{
+			name:        "invalid port type",
+			config:      map[string]any{"host": "example.com", "port": "https"},
+			expectError: true,
+		},
+		{
+			name:        "missing ca_file",
+			config:      map[string]any{"host": "example.com", "ca_file": "/does/not/exist.pem"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TLSCertCheckerSentinel{}
+			err := s.Configure(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, DefaultTLSPort, s.port)
+			assert.Equal(t, "example.com", s.serverName)
+		})
+	}
+}
+
+func TestTLSCertCheckerSentinel_Check(t *testing.T) {
+	tests := []struct {
+		name           string
+		notAfter       time.Time
+		dnsNames       []string
+		serverName     string
+		trustCA        bool
+		expectedStatus signal.Status
+	}{
+		{
+			name:           "healthy - valid for a long time",
+			notAfter:       time.Now().AddDate(1, 0, 0),
+			dnsNames:       []string{"mirante.test"},
+			serverName:     "mirante.test",
+			trustCA:        true,
+			expectedStatus: signal.StatusHealthy,
+		},
+		{
+			name:           "unhealthy - expires soon",
+			notAfter:       time.Now().AddDate(0, 0, 5),
+			dnsNames:       []string{"mirante.test"},
+			serverName:     "mirante.test",
+			trustCA:        true,
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "unhealthy - hostname mismatch",
+			notAfter:       time.Now().AddDate(1, 0, 0),
+			dnsNames:       []string{"other.test"},
+			serverName:     "mirante.test",
+			trustCA:        true,
+			expectedStatus: signal.StatusUnhealthy,
+		},
+		{
+			name:           "unhealthy - untrusted chain",
+			notAfter:       time.Now().AddDate(1, 0, 0),
+			dnsNames:       []string{"mirante.test"},
+			serverName:     "mirante.test",
+			trustCA:        false,
+			expectedStatus: signal.StatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port, caFile := startTLSServer(t, tt.notAfter, tt.dnsNames)
+			config := map[string]any{
+				"host":           host,
+				"port":           port,
+				"server_name":    tt.serverName,
+				"min_days_valid": 14,
+			}
+			if tt.trustCA {
+				config["ca_file"] = caFile
+			}
+
+			s := &TLSCertCheckerSentinel{}
+			require.NoError(t, s.Configure(config))
+
+			sig, err := s.Check(context.Background(), "test-alarm")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, sig.Status, sig.Message)
+			assert.Equal(t, "test-alarm", sig.AlarmID)
+			assert.Contains(t, sig.Message, tt.notAfter.UTC().Format(time.DateOnly))
+			assert.Contains(t, sig.Message, "mirante test")
+		})
+	}
+}
+
+func TestTLSCertCheckerSentinel_CheckConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+
+	s := &TLSCertCheckerSentinel{}
+	require.NoError(t, s.Configure(map[string]any{"host": "127.0.0.1", "port": addr.Port}))
+
+	sig, err := s.Check(context.Background(), "test-alarm")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusUnknown, sig.Status)
+}
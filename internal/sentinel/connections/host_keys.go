@@ -0,0 +1,163 @@
+package connections
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyRepository stores the host keys trusted on first use, keyed by the
+// tunnel address.
+type HostKeyRepository interface {
+	// SetHostKeyIfAbsent records key for address unless one is already
+	// recorded, and returns the key that ends up recorded.
+	SetHostKeyIfAbsent(address string, key string) (string, error)
+}
+
+var (
+	hostKeyRepositoryMu sync.RWMutex
+	hostKeyRepository   HostKeyRepository = NewMemoryHostKeyRepository()
+)
+
+// SetHostKeyRepository sets where trust-on-first-use host keys are recorded.
+// Keys are kept in memory until a persistent repository is set.
+func SetHostKeyRepository(repo HostKeyRepository) {
+	hostKeyRepositoryMu.Lock()
+	defer hostKeyRepositoryMu.Unlock()
+	hostKeyRepository = repo
+}
+
+func getHostKeyRepository() HostKeyRepository {
+	hostKeyRepositoryMu.RLock()
+	defer hostKeyRepositoryMu.RUnlock()
+	return hostKeyRepository
+}
+
+// HostKeyError reports a tunnel host key that failed verification.
+type HostKeyError struct {
+	Address     string
+	Fingerprint string
+	Reason      string
+}
+
+func (e *HostKeyError) Error() string {
+	return fmt.Sprintf("host key verification failed for %s (%s): %s", e.Address, e.Fingerprint, e.Reason)
+}
+
+// hostKeyCallback verifies the bastion against the pinned fingerprints and
+// known_hosts file when either is set, and otherwise trusts the key on first
+// use. Verification is only skipped with InsecureIgnoreHostKey.
+func (c TunnelConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if c.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if c.TrustOnFirstUse || (len(c.HostKeyFingerprints) == 0 && c.KnownHostsPath == "") {
+		return trustOnFirstUse(getHostKeyRepository()), nil
+	}
+
+	var knownHosts ssh.HostKeyCallback
+	if c.KnownHostsPath != "" {
+		var err error
+		knownHosts, err = knownhosts.New(c.KnownHostsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known_hosts: %v", err)
+		}
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+		if slices.Contains(c.HostKeyFingerprints, fingerprint) {
+			return nil
+		}
+		reason := "key does not match any pinned fingerprint"
+		if knownHosts != nil {
+			err := knownHosts(hostname, remote, key)
+			if err == nil {
+				return nil
+			}
+			var keyErr *knownhosts.KeyError
+			switch {
+			case errors.As(err, &keyErr) && len(keyErr.Want) > 0:
+				reason = "key does not match known_hosts, the host key may have changed"
+			case errors.As(err, &keyErr):
+				reason = "host is not listed in known_hosts"
+			default:
+				reason = err.Error()
+			}
+		}
+		return &HostKeyError{Address: hostname, Fingerprint: fingerprint, Reason: reason}
+	}, nil
+}
+
+func trustOnFirstUse(repo HostKeyRepository) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+		presented := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+		recorded, err := repo.SetHostKeyIfAbsent(hostname, presented)
+		if err != nil {
+			return &HostKeyError{Address: hostname, Fingerprint: fingerprint, Reason: fmt.Sprintf("failed to record host key: %v", err)}
+		}
+		if recorded == presented {
+			return nil
+		}
+		recordedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(recorded))
+		if err != nil {
+			return &HostKeyError{Address: hostname, Fingerprint: fingerprint, Reason: fmt.Sprintf("recorded host key is invalid: %v", err)}
+		}
+		if bytes.Equal(recordedKey.Marshal(), key.Marshal()) {
+			return nil
+		}
+		return &HostKeyError{
+			Address:     hostname,
+			Fingerprint: fingerprint,
+			Reason:      fmt.Sprintf("host key changed since it was first trusted as %s", ssh.FingerprintSHA256(recordedKey)),
+		}
+	}
+}
+
+type MemoryHostKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func NewMemoryHostKeyRepository() *MemoryHostKeyRepository {
+	return &MemoryHostKeyRepository{keys: make(map[string]string)}
+}
+
+func (r *MemoryHostKeyRepository) SetHostKeyIfAbsent(address string, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if recorded, ok := r.keys[address]; ok {
+		return recorded, nil
+	}
+	r.keys[address] = key
+	return key, nil
+}
+
+type RedisHostKeyRepository struct {
+	redis *redis.Client
+}
+
+func NewRedisHostKeyRepository(client *redis.Client) *RedisHostKeyRepository {
+	return &RedisHostKeyRepository{redis: client}
+}
+
+func (r *RedisHostKeyRepository) SetHostKeyIfAbsent(address string, key string) (string, error) {
+	redisKey := fmt.Sprintf("ssh_host_key:%s", address)
+	set, err := r.redis.SetNX(context.Background(), redisKey, key, 0).Result()
+	if err != nil {
+		return "", err
+	}
+	if set {
+		return key, nil
+	}
+	return r.redis.Get(context.Background(), redisKey).Result()
+}
@@ -0,0 +1,192 @@
+package connections
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func generateHostKey(t *testing.T) ssh.PublicKey {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(public)
+	require.NoError(t, err)
+	return key
+}
+
+func writeKnownHosts(t *testing.T, config TunnelConfig, key ssh.PublicKey) string {
+	address := knownhosts.Normalize(net.JoinHostPort(config.Host, strconv.Itoa(config.Port)))
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(path, []byte(knownhosts.Line([]string{address}, key)+"\n"), 0o600))
+	return path
+}
+
+func TestNewSSHClient_HostKeyVerification(t *testing.T) {
+	config, hostKey := startSSHServer(t)
+	otherKey := generateHostKey(t)
+
+	tests := []struct {
+		name             string
+		configure        func(c *TunnelConfig)
+		expectHostKeyErr string
+	}{
+		{
+			name: "pinned fingerprint matches",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = []string{ssh.FingerprintSHA256(otherKey), ssh.FingerprintSHA256(hostKey)}
+			},
+		},
+		{
+			name: "pinned fingerprint mismatch",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = []string{ssh.FingerprintSHA256(otherKey)}
+			},
+			expectHostKeyErr: "key does not match any pinned fingerprint",
+		},
+		{
+			name: "known_hosts matches",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = nil
+				c.KnownHostsPath = writeKnownHosts(t, *c, hostKey)
+			},
+		},
+		{
+			name: "known_hosts mismatch",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = nil
+				c.KnownHostsPath = writeKnownHosts(t, *c, otherKey)
+			},
+			expectHostKeyErr: "key does not match known_hosts",
+		},
+		{
+			name: "host not in known_hosts",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = nil
+				other := *c
+				other.Host = "bastion.example.com"
+				c.KnownHostsPath = writeKnownHosts(t, other, hostKey)
+			},
+			expectHostKeyErr: "host is not listed in known_hosts",
+		},
+		{
+			name: "insecure ignore host key",
+			configure: func(c *TunnelConfig) {
+				c.HostKeyFingerprints = nil
+				c.InsecureIgnoreHostKey = true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tunnelConfig := config
+			tt.configure(&tunnelConfig)
+			client, err := NewSSHClient(tunnelConfig)
+			if tt.expectHostKeyErr == "" {
+				require.NoError(t, err)
+				client.Close()
+				return
+			}
+			var hostKeyErr *HostKeyError
+			require.ErrorAs(t, err, &hostKeyErr)
+			assert.Contains(t, hostKeyErr.Error(), tt.expectHostKeyErr)
+			assert.Equal(t, ssh.FingerprintSHA256(hostKey), hostKeyErr.Fingerprint)
+		})
+	}
+}
+
+func TestNewSSHClient_TrustOnFirstUse(t *testing.T) {
+	config, hostKey := startSSHServer(t)
+	config.HostKeyFingerprints = nil
+	repo := NewMemoryHostKeyRepository()
+	SetHostKeyRepository(repo)
+	defer SetHostKeyRepository(NewMemoryHostKeyRepository())
+
+	client, err := NewSSHClient(config)
+	require.NoError(t, err)
+	client.Close()
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	assert.Equal(t, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey))), repo.keys[address])
+
+	client, err = NewSSHClient(config)
+	require.NoError(t, err)
+	client.Close()
+
+	otherKey := generateHostKey(t)
+	repo.keys[address] = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(otherKey)))
+	_, err = NewSSHClient(config)
+	var hostKeyErr *HostKeyError
+	require.ErrorAs(t, err, &hostKeyErr)
+	assert.Equal(t, fmt.Sprintf(
+		"host key verification failed for %s (%s): host key changed since it was first trusted as %s",
+		address, ssh.FingerprintSHA256(hostKey), ssh.FingerprintSHA256(otherKey),
+	), hostKeyErr.Error())
+}
+
+func TestNewTunnelConfig_HostKeyOptions(t *testing.T) {
+	base := func() map[string]any {
+		return map[string]any{"host": "bastion", "port": 22, "user": "monitor", "password": "secret"}
+	}
+
+	config := base()
+	config["host_key_fingerprints"] = []any{"SHA256:abc"}
+	config["known_hosts"] = "/etc/ssh/ssh_known_hosts"
+	tunnel, err := NewTunnelConfig(config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SHA256:abc"}, tunnel.HostKeyFingerprints)
+	assert.Equal(t, "/etc/ssh/ssh_known_hosts", tunnel.KnownHostsPath)
+
+	config = base()
+	config["host_key_fingerprints"] = []any{"ab:cd:ef"}
+	_, err = NewTunnelConfig(config)
+	assert.Error(t, err)
+
+	config = base()
+	config["trust_on_first_use"] = true
+	config["known_hosts"] = "/etc/ssh/ssh_known_hosts"
+	_, err = NewTunnelConfig(config)
+	assert.Error(t, err)
+
+	config = base()
+	config["insecure_ignore_host_key"] = true
+	tunnel, err = NewTunnelConfig(config)
+	require.NoError(t, err)
+	assert.True(t, tunnel.InsecureIgnoreHostKey)
+
+	config = base()
+	config["insecure_ignore_host_key"] = true
+	config["host_key_fingerprints"] = []any{"SHA256:abc"}
+	_, err = NewTunnelConfig(config)
+	assert.Error(t, err)
+}
+
+func TestNewSSHClient_DefaultsToTrustOnFirstUse(t *testing.T) {
+	config, _ := startSSHServer(t)
+	config.HostKeyFingerprints = nil
+	repo := NewMemoryHostKeyRepository()
+	SetHostKeyRepository(repo)
+	defer SetHostKeyRepository(NewMemoryHostKeyRepository())
+
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	repo.keys[address] = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(generateHostKey(t))))
+	_, err := NewSSHClient(config)
+	var hostKeyErr *HostKeyError
+	require.ErrorAs(t, err, &hostKeyErr)
+	assert.Contains(t, hostKeyErr.Reason, "host key changed since it was first trusted")
+
+	config.InsecureIgnoreHostKey = true
+	client, err := NewSSHClient(config)
+	require.NoError(t, err)
+	client.Close()
+}
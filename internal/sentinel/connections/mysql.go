@@ -8,7 +8,6 @@ import (
 	"net"
 
 	"github.com/go-sql-driver/mysql"
-	"golang.org/x/crypto/ssh"
 )
 
 type MySQLConnectionConfig struct {
@@ -21,8 +20,8 @@ type MySQLConnectionConfig struct {
 }
 
 type MySQLConnection struct {
-	DB        *sql.DB
-	sshClient *ssh.Client
+	DB     *sql.DB
+	tunnel *SSHTunnel
 }
 
 func getIntValue(value any) int {
@@ -76,33 +75,38 @@ func NewMySQLConnectionConfig(config map[string]any) (*MySQLConnectionConfig, er
 }
 
 func (c *MySQLConnection) Close() error {
-	var dbErr, sshErr error
+	var dbErr, tunnelErr error
 	if c.DB != nil {
 		dbErr = c.DB.Close()
 	}
-	if c.sshClient != nil {
-		sshErr = c.sshClient.Close()
+	if c.tunnel != nil {
+		tunnelErr = c.tunnel.Close()
 	}
 	if dbErr != nil {
 		return dbErr
 	}
-	return sshErr
+	return tunnelErr
 }
 
 func NewMySQLConnection(config MySQLConnectionConfig) (*MySQLConnection, error) {
 	var db *sql.DB
-	var sshClient *ssh.Client
+	var tunnel *SSHTunnel
 	var err error
 
 	if config.Tunnel.Host != "" {
-		sshClient, err = NewSSHClient(config.Tunnel)
+		tunnel, err = OpenTunnel(config.Tunnel)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create SSH client: %v", err)
 		}
 
-		dialName := "mysql+ssh"
+		// Dial functions are registered globally by name, so each tunnel gets
+		// its own name to keep databases behind different bastions apart.
+		// Another connection may re-register the name, so the dialer leases
+		// the tunnel per connection rather than capturing this one's lease.
+		dialName := "mysql+ssh+" + config.Tunnel.poolKey()
+		tunnelConfig := config.Tunnel
 		mysql.RegisterDialContext(dialName, func(ctx context.Context, addr string) (net.Conn, error) {
-			return sshClient.Dial("tcp", addr)
+			return defaultTunnelPool.DialContext(ctx, tunnelConfig, "tcp", addr)
 		})
 
 		dsn := fmt.Sprintf("%s:%s@%s(%s:%d)/%s",
@@ -116,7 +120,7 @@ func NewMySQLConnection(config MySQLConnectionConfig) (*MySQLConnection, error)
 
 		db, err = sql.Open("mysql", dsn)
 		if err != nil {
-			sshClient.Close()
+			tunnel.Close()
 			return nil, fmt.Errorf("failed to connect to database via SSH tunnel: %v", err)
 		}
 	} else {
@@ -135,7 +139,7 @@ func NewMySQLConnection(config MySQLConnectionConfig) (*MySQLConnection, error)
 	}
 
 	return &MySQLConnection{
-		DB:        db,
-		sshClient: sshClient,
+		DB:     db,
+		tunnel: tunnel,
 	}, nil
 }
@@ -0,0 +1,71 @@
+package connections
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startMySQLErrorServer greets every connection with a "Too many connections"
+// error packet, which is enough for the driver to prove it reached the server.
+func startMySQLErrorServer(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	message := "Too many connections"
+	payload := append([]byte{0xff, 0x10, 0x04}, message...)
+	packet := append([]byte{byte(len(payload)), 0, 0, 0}, payload...)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write(packet)
+			conn.Close()
+		}
+	}()
+	host, portText, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	return host, port
+}
+
+func TestNewMySQLConnection_SharedTunnel(t *testing.T) {
+	tunnel, _ := startSSHServer(t)
+	host, port := startMySQLErrorServer(t)
+	config := MySQLConnectionConfig{
+		Host:     host,
+		Port:     port,
+		User:     "monitor",
+		Password: "secret",
+		Database: "app",
+		Tunnel:   tunnel,
+	}
+
+	first, err := NewMySQLConnection(config)
+	require.NoError(t, err)
+	second, err := NewMySQLConnection(config)
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	// Drop the shared client, e.g. the bastion restarted. The second database
+	// must dial through a fresh client rather than a lease captured earlier.
+	second.tunnel.entry.client.Close()
+
+	var mysqlErr *mysql.MySQLError
+	require.ErrorAs(t, second.DB.Ping(), &mysqlErr)
+	assert.Equal(t, uint16(1040), mysqlErr.Number)
+	require.NoError(t, second.Close())
+
+	defaultTunnelPool.mu.Lock()
+	defer defaultTunnelPool.mu.Unlock()
+	entry, ok := defaultTunnelPool.clients[tunnel.poolKey()]
+	require.True(t, ok)
+	assert.NotSame(t, second.tunnel.entry, entry)
+	assert.Zero(t, entry.refs)
+}
@@ -4,7 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type PostgresConnectionConfig struct {
@@ -16,10 +16,12 @@ type PostgresConnectionConfig struct {
 	SSLMode     string
 	SSLRootCert string
 	SSLVerify   bool
+	Tunnel      TunnelConfig
 }
 
 type PostgresConnection struct {
-	DB *sql.DB
+	DB     *sql.DB
+	tunnel *SSHTunnel
 }
 
 func NewPostgresConnectionConfig(config map[string]any) (*PostgresConnectionConfig, error) {
@@ -49,6 +51,14 @@ func NewPostgresConnectionConfig(config map[string]any) (*PostgresConnectionConf
 		c.SSLVerify = sslVerify
 	}
 
+	if tunnelConfig, ok := config["tunnel"].(map[string]any); ok {
+		tunnel, err := NewTunnelConfig(tunnelConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tunnel config: %v", err)
+		}
+		c.Tunnel = *tunnel
+	}
+
 	if c.Host == "" {
 		return nil, fmt.Errorf("missing required field: host")
 	}
@@ -69,7 +79,13 @@ func NewPostgresConnectionConfig(config map[string]any) (*PostgresConnectionConf
 }
 
 func (c *PostgresConnection) Close() error {
-	return c.DB.Close()
+	dbErr := c.DB.Close()
+	if c.tunnel != nil {
+		if err := c.tunnel.Close(); err != nil && dbErr == nil {
+			return err
+		}
+	}
+	return dbErr
 }
 
 func NewPostgresConnection(config PostgresConnectionConfig) (*PostgresConnection, error) {
@@ -89,17 +105,36 @@ func NewPostgresConnection(config PostgresConnectionConfig) (*PostgresConnection
 		dsn += " sslrootcert= sslcert= sslkey="
 	}
 
-	db, err = sql.Open("postgres", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	var tunnel *SSHTunnel
+	if config.Tunnel.Host != "" {
+		tunnel, err = OpenTunnel(config.Tunnel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSH client: %v", err)
+		}
+		connector, err := pq.NewConnector(dsn)
+		if err != nil {
+			tunnel.Close()
+			return nil, fmt.Errorf("failed to connect to database via SSH tunnel: %v", err)
+		}
+		connector.Dialer(tunnel)
+		db = sql.OpenDB(connector)
+	} else {
+		db, err = sql.Open("postgres", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %v", err)
+		}
 	}
 
 	if err = db.Ping(); err != nil {
 		db.Close()
+		if tunnel != nil {
+			tunnel.Close()
+		}
 		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
 
 	return &PostgresConnection{
-		DB: db,
+		DB:     db,
+		tunnel: tunnel,
 	}, nil
 }
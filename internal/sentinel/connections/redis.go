@@ -0,0 +1,117 @@
+package connections
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisConnectionConfig struct {
+	Address  string
+	Username string
+	Password string
+	DB       int
+	// TLS enables TLS when set. It is left to callers to build it from their
+	// own certificate options.
+	TLS    *tls.Config
+	Tunnel TunnelConfig
+}
+
+type RedisConnection struct {
+	Client *redis.Client
+	tunnel *SSHTunnel
+}
+
+func NewRedisConnectionConfig(config map[string]any) (*RedisConnectionConfig, error) {
+	address, ok := config["address"].(string)
+	if !ok || address == "" {
+		return nil, fmt.Errorf("missing required field: address")
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid address %s: %v", address, err)
+	}
+	c := RedisConnectionConfig{
+		Address: address,
+	}
+	if username, ok := config["username"].(string); ok {
+		c.Username = username
+	}
+	if password, ok := config["password"].(string); ok {
+		c.Password = password
+	}
+	switch db := config["db"].(type) {
+	case nil:
+	case int:
+		c.DB = db
+	case float64:
+		c.DB = int(db)
+	default:
+		return nil, fmt.Errorf("db must be a number: %v", db)
+	}
+	if c.DB < 0 {
+		return nil, fmt.Errorf("db must not be negative")
+	}
+	if tunnelConfig, ok := config["tunnel"].(map[string]any); ok {
+		tunnel, err := NewTunnelConfig(tunnelConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tunnel config: %v", err)
+		}
+		c.Tunnel = *tunnel
+	}
+	return &c, nil
+}
+
+func (c *RedisConnection) Close() error {
+	clientErr := c.Client.Close()
+	if c.tunnel != nil {
+		if err := c.tunnel.Close(); err != nil {
+			return err
+		}
+	}
+	return clientErr
+}
+
+func NewRedisConnection(config RedisConnectionConfig) (*RedisConnection, error) {
+	options := &redis.Options{
+		Addr:      config.Address,
+		Username:  config.Username,
+		Password:  config.Password,
+		DB:        config.DB,
+		TLSConfig: config.TLS,
+		// Checks run once per interval, there is nothing to gain from
+		// keeping idle connections or retrying behind the sentinel's back.
+		MaxRetries: -1,
+		PoolSize:   1,
+	}
+
+	var tunnel *SSHTunnel
+	if config.Tunnel.Host != "" {
+		var err error
+		tunnel, err = OpenTunnel(config.Tunnel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSH client: %v", err)
+		}
+		// go-redis ignores TLSConfig when a custom dialer is set, so TLS is
+		// layered over the tunnel here.
+		tlsConfig := config.TLS
+		options.Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := tunnel.DialContext(ctx, network, addr)
+			if err != nil || tlsConfig == nil {
+				return conn, err
+			}
+			clientConfig := tlsConfig.Clone()
+			if clientConfig.ServerName == "" {
+				clientConfig.ServerName, _, _ = net.SplitHostPort(addr)
+			}
+			return tls.Client(conn, clientConfig), nil
+		}
+	}
+
+	return &RedisConnection{
+		Client: redis.NewClient(options),
+		tunnel: tunnel,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package connections
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type SQLiteConnectionConfig struct {
+	Path     string
+	ReadOnly bool
+}
+
+type SQLiteConnection struct {
+	DB *sql.DB
+}
+
+func NewSQLiteConnectionConfig(config map[string]any) (*SQLiteConnectionConfig, error) {
+	path, ok := config["path"].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("missing required field: path")
+	}
+	c := SQLiteConnectionConfig{
+		Path:     path,
+		ReadOnly: true,
+	}
+	if readOnly, ok := config["read_only"].(bool); ok {
+		c.ReadOnly = readOnly
+	}
+	return &c, nil
+}
+
+func (c *SQLiteConnection) Close() error {
+	return c.DB.Close()
+}
+
+func NewSQLiteConnection(config SQLiteConnectionConfig) (*SQLiteConnection, error) {
+	params := url.Values{}
+	if config.ReadOnly {
+		params.Set("mode", "ro")
+	}
+	dsn := "file:" + config.Path
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	return &SQLiteConnection{
+		DB: db,
+	}, nil
+}
@@ -2,7 +2,9 @@ package connections
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -14,6 +16,13 @@ type TunnelConfig struct {
 	User       string
 	Password   string
 	PrivateKey string
+
+	HostKeyFingerprints []string
+	KnownHostsPath      string
+	TrustOnFirstUse     bool
+	// InsecureIgnoreHostKey skips host key verification. Without it, and
+	// without pinned fingerprints or known_hosts, keys are trusted on first use.
+	InsecureIgnoreHostKey bool
 }
 
 func NewTunnelConfig(config map[string]any) (*TunnelConfig, error) {
@@ -38,6 +47,34 @@ func NewTunnelConfig(config map[string]any) (*TunnelConfig, error) {
 		c.PrivateKey = string(decodedKey)
 
 	}
+	if fingerprints, ok := config["host_key_fingerprints"]; ok {
+		list, ok := fingerprints.([]any)
+		if !ok {
+			return nil, fmt.Errorf("host_key_fingerprints must be a list")
+		}
+		for _, value := range list {
+			fingerprint, ok := value.(string)
+			if !ok || !strings.HasPrefix(fingerprint, "SHA256:") {
+				return nil, fmt.Errorf("host key fingerprints must be SHA256:... strings, got %v", value)
+			}
+			c.HostKeyFingerprints = append(c.HostKeyFingerprints, fingerprint)
+		}
+	}
+	if knownHosts, ok := config["known_hosts"].(string); ok {
+		c.KnownHostsPath = knownHosts
+	}
+	if tofu, ok := config["trust_on_first_use"].(bool); ok {
+		c.TrustOnFirstUse = tofu
+	}
+	if insecure, ok := config["insecure_ignore_host_key"].(bool); ok {
+		c.InsecureIgnoreHostKey = insecure
+	}
+	if c.TrustOnFirstUse && (len(c.HostKeyFingerprints) > 0 || c.KnownHostsPath != "") {
+		return nil, fmt.Errorf("trust_on_first_use can't be combined with host_key_fingerprints or known_hosts")
+	}
+	if c.InsecureIgnoreHostKey && (c.TrustOnFirstUse || len(c.HostKeyFingerprints) > 0 || c.KnownHostsPath != "") {
+		return nil, fmt.Errorf("insecure_ignore_host_key can't be combined with other host key options")
+	}
 	if c.Host != "" {
 		if c.PrivateKey == "" && c.Password == "" {
 			return nil, fmt.Errorf("missing required field: private_key or password")
@@ -54,30 +91,32 @@ func NewTunnelConfig(config map[string]any) (*TunnelConfig, error) {
 
 func NewSSHClient(config TunnelConfig) (*ssh.Client, error) {
 	tunnelAddr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	var sshConfig *ssh.ClientConfig
+	hostKeyCallback, err := config.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	sshConfig := &ssh.ClientConfig{
+		User:            config.User,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         40 * time.Second,
+	}
 
 	if config.PrivateKey != "" {
 		signer, err := ssh.ParsePrivateKey([]byte(config.PrivateKey))
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse private key: %v", err)
 		}
-		sshConfig = &ssh.ClientConfig{
-			User:            config.User,
-			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-			Timeout:         40 * time.Second,
-		}
+		sshConfig.Auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
 	} else {
-		sshConfig = &ssh.ClientConfig{
-			User:            config.User,
-			Auth:            []ssh.AuthMethod{ssh.Password(config.Password)},
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-			Timeout:         40 * time.Second,
-		}
+		sshConfig.Auth = []ssh.AuthMethod{ssh.Password(config.Password)}
 	}
 
 	sshClient, err := ssh.Dial("tcp", tunnelAddr, sshConfig)
 	if err != nil {
+		var hostKeyErr *HostKeyError
+		if errors.As(err, &hostKeyErr) {
+			return nil, hostKeyErr
+		}
 		return nil, fmt.Errorf("failed to establish SSH tunnel: %v", err)
 	}
 	return sshClient, nil
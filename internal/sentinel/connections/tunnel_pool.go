@@ -0,0 +1,257 @@
+package connections
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// DefaultTunnelIdleTimeout is how long an unused SSH client stays open in the
+// pool, waiting to be reused by the next check going through the same bastion.
+const DefaultTunnelIdleTimeout = 5 * time.Minute
+
+// DefaultTunnelKeepaliveTimeout bounds the keepalive sent before a pooled
+// client is reused. A bastion that doesn't answer in time, e.g. behind a
+// half-open connection, is treated as dead and redialed.
+const DefaultTunnelKeepaliveTimeout = 5 * time.Second
+
+var defaultTunnelPool = NewTunnelPool(DefaultTunnelIdleTimeout)
+
+// TunnelPool shares SSH clients between connections using the same tunnel
+// config. Clients are reference counted and closed once they have been idle
+// for longer than the pool's idle timeout.
+type TunnelPool struct {
+	idleTimeout      time.Duration
+	keepaliveTimeout time.Duration
+	dial             func(TunnelConfig) (*ssh.Client, error)
+
+	mu        sync.Mutex
+	clients   map[string]*pooledSSHClient
+	dialLocks map[string]*dialLock
+}
+
+// dialLock serializes Open calls for one tunnel. It is dropped from the pool
+// once no Open for the tunnel is in progress.
+type dialLock struct {
+	sync.Mutex
+	users int
+}
+
+type pooledSSHClient struct {
+	client    *ssh.Client
+	refs      int
+	idleTimer *time.Timer
+}
+
+// SSHTunnel is a lease on a pooled SSH client. It must be closed to give the
+// client back to the pool.
+type SSHTunnel struct {
+	pool  *TunnelPool
+	key   string
+	entry *pooledSSHClient
+	once  sync.Once
+}
+
+func NewTunnelPool(idleTimeout time.Duration) *TunnelPool {
+	return &TunnelPool{
+		idleTimeout:      idleTimeout,
+		keepaliveTimeout: DefaultTunnelKeepaliveTimeout,
+		dial:             NewSSHClient,
+		clients:          make(map[string]*pooledSSHClient),
+		dialLocks:        make(map[string]*dialLock),
+	}
+}
+
+// OpenTunnel leases an SSH client for the given config from the default pool.
+func OpenTunnel(config TunnelConfig) (*SSHTunnel, error) {
+	return defaultTunnelPool.Open(config)
+}
+
+func (c TunnelConfig) poolKey() string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%d\x00%s\x00%s\x00%s\x00%v\x00%s\x00%t\x00%t",
+		c.Host, c.Port, c.User, c.Password, c.PrivateKey, c.HostKeyFingerprints, c.KnownHostsPath, c.TrustOnFirstUse, c.InsecureIgnoreHostKey)
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func (p *TunnelPool) Open(config TunnelConfig) (*SSHTunnel, error) {
+	key := config.poolKey()
+
+	p.mu.Lock()
+	lock, ok := p.dialLocks[key]
+	if !ok {
+		lock = &dialLock{}
+		p.dialLocks[key] = lock
+	}
+	lock.users++
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(p.dialLocks, key)
+		}
+		p.mu.Unlock()
+	}()
+
+	// Serialize dials per tunnel so concurrent checks share a single client
+	// instead of racing to open several.
+	lock.Lock()
+	defer lock.Unlock()
+
+	p.mu.Lock()
+	entry, ok := p.clients[key]
+	if ok {
+		entry.refs++
+		if entry.idleTimer != nil {
+			entry.idleTimer.Stop()
+			entry.idleTimer = nil
+		}
+	}
+	p.mu.Unlock()
+
+	if ok {
+		if isAlive(entry.client, p.keepaliveTimeout) {
+			return &SSHTunnel{pool: p, key: key, entry: entry}, nil
+		}
+		p.mu.Lock()
+		if p.clients[key] == entry {
+			delete(p.clients, key)
+		}
+		p.mu.Unlock()
+		p.release(key, entry)
+	}
+
+	client, err := p.dial(config)
+	if err != nil {
+		return nil, err
+	}
+	entry = &pooledSSHClient{client: client, refs: 1}
+	p.mu.Lock()
+	p.clients[key] = entry
+	p.mu.Unlock()
+	return &SSHTunnel{pool: p, key: key, entry: entry}, nil
+}
+
+func (p *TunnelPool) release(key string, entry *pooledSSHClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry.refs--
+	if entry.refs > 0 {
+		return
+	}
+	if p.clients[key] != entry {
+		entry.client.Close()
+		return
+	}
+	entry.idleTimer = time.AfterFunc(p.idleTimeout, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if entry.refs > 0 || p.clients[key] != entry {
+			return
+		}
+		delete(p.clients, key)
+		entry.client.Close()
+	})
+}
+
+// Close closes every pooled client, including ones still leased.
+func (p *TunnelPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for key, entry := range p.clients {
+		if entry.idleTimer != nil {
+			entry.idleTimer.Stop()
+		}
+		if err := entry.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.clients, key)
+	}
+	return firstErr
+}
+
+// isAlive sends a keepalive and waits at most timeout for the reply. A client
+// that times out is closed once released, which also ends the pending request.
+func isAlive(client *ssh.Client, timeout time.Duration) bool {
+	reply := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		reply <- err
+	}()
+	select {
+	case err := <-reply:
+		return err == nil
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// DialContext dials addr through a client leased for as long as the returned
+// connection stays open, for drivers that dial outside of any SSHTunnel.
+func (p *TunnelPool) DialContext(ctx context.Context, config TunnelConfig, network, addr string) (net.Conn, error) {
+	tunnel, err := p.Open(config)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := tunnel.DialContext(ctx, network, addr)
+	if err != nil {
+		tunnel.Close()
+		return nil, err
+	}
+	return &tunnelConn{Conn: conn, tunnel: tunnel}, nil
+}
+
+// tunnelConn gives its lease back to the pool once closed.
+type tunnelConn struct {
+	net.Conn
+	tunnel *SSHTunnel
+}
+
+func (c *tunnelConn) Close() error {
+	err := c.Conn.Close()
+	c.tunnel.Close()
+	return err
+}
+
+func (t *SSHTunnel) Dial(network, addr string) (net.Conn, error) {
+	return t.entry.client.Dial(network, addr)
+}
+
+func (t *SSHTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return t.entry.client.DialContext(ctx, network, addr)
+}
+
+func (t *SSHTunnel) DialTimeout(network, addr string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return t.entry.client.DialContext(ctx, network, addr)
+}
+
+// Close returns the SSH client to the pool. It is safe to call more than once.
+func (t *SSHTunnel) Close() error {
+	t.once.Do(func() {
+		t.pool.release(t.key, t.entry)
+	})
+	return nil
+}
+
+// NewSession opens a session on the pooled SSH client, e.g. to run a command
+// on the bastion itself.
+func (t *SSHTunnel) NewSession() (*ssh.Session, error) {
+	return t.entry.client.NewSession()
+}
+
+// NewSFTPClient opens an SFTP session on the pooled SSH client to inspect
+// files on the remote host. The SFTP client must be closed before the tunnel.
+func (t *SSHTunnel) NewSFTPClient() (*sftp.Client, error) {
+	return sftp.NewClient(t.entry.client)
+}
@@ -0,0 +1,322 @@
+package connections
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// startSSHServer runs an SSH server accepting password "secret" that forwards
+// direct-tcpip channels, like a bastion host.
+func startSSHServer(t *testing.T) (TunnelConfig, ssh.PublicKey) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "monitor" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHConn(conn, serverConfig)
+		}
+	}()
+
+	host, portText, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	return TunnelConfig{
+		Host:                host,
+		Port:                port,
+		User:                "monitor",
+		Password:            "secret",
+		HostKeyFingerprints: []string{ssh.FingerprintSHA256(signer.PublicKey())},
+	}, signer.PublicKey()
+}
+
+func serveSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		go forwardDirectTCPIP(newChannel)
+	}
+}
+
+func forwardDirectTCPIP(newChannel ssh.NewChannel) {
+	extra := newChannel.ExtraData()
+	hostLength := binary.BigEndian.Uint32(extra)
+	host := string(extra[4 : 4+hostLength])
+	port := binary.BigEndian.Uint32(extra[4+hostLength:])
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		io.Copy(channel, target)
+		channel.Close()
+	}()
+	io.Copy(target, channel)
+	target.Close()
+}
+
+func startEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func newCountingTunnelPool(idleTimeout time.Duration) (*TunnelPool, *atomic.Int32) {
+	pool := NewTunnelPool(idleTimeout)
+	var dials atomic.Int32
+	pool.dial = func(config TunnelConfig) (*ssh.Client, error) {
+		dials.Add(1)
+		return NewSSHClient(config)
+	}
+	return pool, &dials
+}
+
+func assertTunnelEchoes(t *testing.T, tunnel *SSHTunnel, addr string) {
+	conn, err := tunnel.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+}
+
+func TestTunnelPool_ReusesClient(t *testing.T) {
+	config, _ := startSSHServer(t)
+	echoAddr := startEchoServer(t)
+	pool, dials := newCountingTunnelPool(time.Minute)
+	defer pool.Close()
+
+	first, err := pool.Open(config)
+	require.NoError(t, err)
+	second, err := pool.Open(config)
+	require.NoError(t, err)
+	assertTunnelEchoes(t, first, echoAddr)
+	assertTunnelEchoes(t, second, echoAddr)
+	first.Close()
+	second.Close()
+
+	third, err := pool.Open(config)
+	require.NoError(t, err)
+	defer third.Close()
+	assertTunnelEchoes(t, third, echoAddr)
+	assert.Equal(t, int32(1), dials.Load())
+}
+
+func TestTunnelPool_ClosesIdleClients(t *testing.T) {
+	config, _ := startSSHServer(t)
+	echoAddr := startEchoServer(t)
+	pool, dials := newCountingTunnelPool(50 * time.Millisecond)
+	defer pool.Close()
+
+	tunnel, err := pool.Open(config)
+	require.NoError(t, err)
+	closed := make(chan struct{})
+	go func() {
+		tunnel.entry.client.Wait()
+		close(closed)
+	}()
+	tunnel.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("idle client was not closed")
+	}
+
+	tunnel, err = pool.Open(config)
+	require.NoError(t, err)
+	defer tunnel.Close()
+	assertTunnelEchoes(t, tunnel, echoAddr)
+	assert.Equal(t, int32(2), dials.Load())
+}
+
+func TestTunnelPool_RedialsDeadClients(t *testing.T) {
+	config, _ := startSSHServer(t)
+	echoAddr := startEchoServer(t)
+	pool, dials := newCountingTunnelPool(time.Minute)
+	defer pool.Close()
+
+	tunnel, err := pool.Open(config)
+	require.NoError(t, err)
+	tunnel.entry.client.Close()
+	tunnel.Close()
+
+	tunnel, err = pool.Open(config)
+	require.NoError(t, err)
+	defer tunnel.Close()
+	assertTunnelEchoes(t, tunnel, echoAddr)
+	assert.Equal(t, int32(2), dials.Load())
+}
+
+func TestTunnelPool_SeparatesConfigs(t *testing.T) {
+	config, _ := startSSHServer(t)
+	pool, dials := newCountingTunnelPool(time.Minute)
+	defer pool.Close()
+
+	first, err := pool.Open(config)
+	require.NoError(t, err)
+	defer first.Close()
+
+	_, err = pool.Open(TunnelConfig{Host: config.Host, Port: config.Port, User: "monitor", Password: "wrong"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), dials.Load())
+}
+
+// startFreezableProxy forwards TCP connections to target until frozen, after
+// which it silently drops all traffic, like a half-open connection.
+func startFreezableProxy(t *testing.T, target string) (string, *atomic.Bool) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	var frozen atomic.Bool
+	forward := func(dst io.Writer, src io.Reader) {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if err != nil {
+				return
+			}
+			if !frozen.Load() {
+				dst.Write(buf[:n])
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			t.Cleanup(func() { conn.Close(); upstream.Close() })
+			go forward(upstream, conn)
+			go forward(conn, upstream)
+		}
+	}()
+	return listener.Addr().String(), &frozen
+}
+
+func TestTunnelPool_RedialsUnresponsiveClients(t *testing.T) {
+	config, _ := startSSHServer(t)
+	proxyAddr, frozen := startFreezableProxy(t, net.JoinHostPort(config.Host, strconv.Itoa(config.Port)))
+	proxyHost, proxyPort, err := net.SplitHostPort(proxyAddr)
+	require.NoError(t, err)
+	echoAddr := startEchoServer(t)
+
+	pool, dials := newCountingTunnelPool(time.Minute)
+	pool.keepaliveTimeout = 100 * time.Millisecond
+	pool.dial = func(c TunnelConfig) (*ssh.Client, error) {
+		// The first client goes through the proxy, later ones straight to
+		// the server.
+		if dials.Add(1) == 1 {
+			c.Host = proxyHost
+			c.Port, _ = strconv.Atoi(proxyPort)
+		}
+		return NewSSHClient(c)
+	}
+	defer pool.Close()
+
+	tunnel, err := pool.Open(config)
+	require.NoError(t, err)
+	tunnel.Close()
+	frozen.Store(true)
+
+	opened := make(chan error, 1)
+	go func() {
+		tunnel, err = pool.Open(config)
+		opened <- err
+	}()
+	select {
+	case err := <-opened:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Open blocked on an unresponsive client")
+	}
+	defer tunnel.Close()
+	assertTunnelEchoes(t, tunnel, echoAddr)
+	assert.Equal(t, int32(2), dials.Load())
+}
+
+func TestTunnelPool_DropsDialLocks(t *testing.T) {
+	config, _ := startSSHServer(t)
+	pool, _ := newCountingTunnelPool(time.Minute)
+	defer pool.Close()
+
+	tunnel, err := pool.Open(config)
+	require.NoError(t, err)
+	tunnel.Close()
+	_, err = pool.Open(TunnelConfig{Host: config.Host, Port: config.Port, User: "monitor", Password: "wrong"})
+	assert.Error(t, err)
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	assert.Empty(t, pool.dialLocks)
+}
@@ -14,6 +14,11 @@ func (f *SentinelFactory) Register(sentinelType string, factory func() Sentinel)
 	f.sentinels[sentinelType] = factory
 }
 
+func (f *SentinelFactory) Has(sentinelType string) bool {
+	_, exists := f.sentinels[sentinelType]
+	return exists
+}
+
 func (f *SentinelFactory) Create(sentinelType string) (Sentinel, error) {
 	factory, exists := f.sentinels[sentinelType]
 	if !exists {
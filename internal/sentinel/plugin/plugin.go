@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+const (
+	DefaultDescribeTimeout  = 10 * time.Second
+	DefaultConfigureTimeout = 10 * time.Second
+	DefaultCheckTimeout     = 60 * time.Second
+)
+
+// Plugin is an executable advertising a sentinel type.
+type Plugin struct {
+	Path string
+	Type string
+}
+
+// Discover describes every executable file in dir. Executables that fail to
+// describe themselves are logged and skipped so one broken plugin doesn't keep
+// the others from loading.
+func Discover(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %v", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var plugins []Plugin
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		pluginType, err := describe(path)
+		if err != nil {
+			log.Printf("Skipping plugin %s: %v", path, err)
+			continue
+		}
+		plugins = append(plugins, Plugin{Path: path, Type: pluginType})
+	}
+	return plugins, nil
+}
+
+func describe(path string) (string, error) {
+	proc, err := startProcess(path)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDescribeTimeout)
+	defer cancel()
+	response, err := proc.call(ctx, Request{Method: MethodDescribe})
+	if err != nil {
+		proc.kill()
+		return "", err
+	}
+	proc.close()
+	if response.Type == "" {
+		return "", fmt.Errorf("plugin did not advertise a sentinel type")
+	}
+	return response.Type, nil
+}
+
+// Register discovers the plugins in dir and registers each one under its
+// advertised type. Types already registered, such as builtins, are not
+// overridden.
+func Register(f *sentinel.SentinelFactory, dir string) error {
+	plugins, err := Discover(dir)
+	if err != nil {
+		return err
+	}
+	for _, p := range plugins {
+		if f.Has(p.Type) {
+			log.Printf("Skipping plugin %s: sentinel type %s is already registered", p.Path, p.Type)
+			continue
+		}
+		path := p.Path
+		f.Register(p.Type, func() sentinel.Sentinel {
+			return NewPluginSentinel(path)
+		})
+	}
+	return nil
+}
+
+// PluginSentinel runs a plugin process per check: the process is started and
+// configured in Configure, answers a single check and is then shut down.
+type PluginSentinel struct {
+	path    string
+	process *process
+}
+
+func NewPluginSentinel(path string) *PluginSentinel {
+	return &PluginSentinel{path: path}
+}
+
+func (s *PluginSentinel) Configure(config map[string]any) error {
+	proc, err := startProcess(s.path)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultConfigureTimeout)
+	defer cancel()
+	if _, err := proc.call(ctx, Request{Method: MethodConfigure, Config: config}); err != nil {
+		proc.kill()
+		return err
+	}
+	s.process = proc
+	return nil
+}
+
+func (s *PluginSentinel) Check(ctx context.Context, alarmID string) (signal.Signal, error) {
+	if s.process == nil {
+		return signal.Signal{}, fmt.Errorf("plugin %s is not configured", s.path)
+	}
+	defer s.process.close()
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultCheckTimeout)
+	defer cancel()
+	response, err := s.process.call(ctx, Request{Method: MethodCheck, AlarmID: alarmID})
+	if err != nil {
+		return signal.Signal{}, err
+	}
+	if !response.Status.IsValid() {
+		return signal.Signal{}, fmt.Errorf("plugin %s returned unsupported status %q", s.path, response.Status)
+	}
+	return signal.Signal{
+		AlarmID:   alarmID,
+		Status:    response.Status,
+		Timestamp: time.Now(),
+		Message:   response.Message,
+		Metrics:   response.Metrics,
+		Labels:    response.Labels,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/sentinel"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakePluginEnv = "MIRANTE_FAKE_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakePluginEnv); mode != "" {
+		runFakePlugin(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFakePlugin lets the test binary act as a plugin executable.
+func runFakePlugin(mode string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	var config map[string]any
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(2)
+		}
+		response := Response{ProtocolVersion: ProtocolVersion}
+		if mode == "old-protocol" {
+			response.ProtocolVersion = 0
+		}
+		switch req.Method {
+		case MethodDescribe:
+			if mode != "untyped" {
+				response.Type = "fake-" + mode
+			}
+		case MethodConfigure:
+			config = req.Config
+			if _, ok := config["status"]; !ok {
+				response.Error = "missing required field: status"
+			}
+		case MethodCheck:
+			if mode == "hang" {
+				time.Sleep(time.Minute)
+			}
+			response.Status = signal.Status(fmt.Sprint(config["status"]))
+			response.Message = "checked " + req.AlarmID
+		default:
+			response.Error = "unsupported method " + req.Method
+		}
+		encoder.Encode(response)
+	}
+}
+
+func writeFakePlugin(t *testing.T, dir string, mode string) string {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	path := filepath.Join(dir, mode)
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q\n", fakePluginEnv, mode, executable)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeFakePlugin(t, dir, "disk")
+	writeFakePlugin(t, dir, "billing")
+	writeFakePlugin(t, dir, "old-protocol")
+	writeFakePlugin(t, dir, "untyped")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0o644))
+
+	plugins, err := Discover(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Plugin{
+		{Path: filepath.Join(dir, "billing"), Type: "fake-billing"},
+		{Path: filepath.Join(dir, "disk"), Type: "fake-disk"},
+	}, plugins)
+
+	_, err = Discover(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	dir := t.TempDir()
+	writeFakePlugin(t, dir, "disk")
+	writeFakePlugin(t, dir, "builtin")
+
+	factory := sentinel.NewFactory()
+	builtin := NewPluginSentinel("builtin")
+	factory.Register("fake-builtin", func() sentinel.Sentinel { return builtin })
+	require.NoError(t, Register(factory, dir))
+
+	s, err := factory.Create("fake-disk")
+	require.NoError(t, err)
+	assert.IsType(t, &PluginSentinel{}, s)
+
+	s, err = factory.Create("fake-builtin")
+	require.NoError(t, err)
+	assert.Same(t, builtin, s)
+}
+
+func TestPluginSentinel(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("configure and check", func(t *testing.T) {
+		s := NewPluginSentinel(writeFakePlugin(t, dir, "disk"))
+		require.NoError(t, s.Configure(map[string]any{"status": "unhealthy"}))
+
+		sig, err := s.Check(context.Background(), "test-alarm")
+		require.NoError(t, err)
+		assert.Equal(t, "test-alarm", sig.AlarmID)
+		assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+		assert.Equal(t, "checked test-alarm", sig.Message)
+	})
+
+	t.Run("configure error", func(t *testing.T) {
+		s := NewPluginSentinel(writeFakePlugin(t, dir, "disk"))
+		err := s.Configure(map[string]any{})
+		assert.EqualError(t, err, "missing required field: status")
+	})
+
+	t.Run("unsupported status", func(t *testing.T) {
+		s := NewPluginSentinel(writeFakePlugin(t, dir, "disk"))
+		require.NoError(t, s.Configure(map[string]any{"status": "degraded"}))
+		_, err := s.Check(context.Background(), "test-alarm")
+		assert.ErrorContains(t, err, `unsupported status "degraded"`)
+	})
+
+	t.Run("protocol version mismatch", func(t *testing.T) {
+		s := NewPluginSentinel(writeFakePlugin(t, dir, "old-protocol"))
+		err := s.Configure(map[string]any{"status": "healthy"})
+		assert.ErrorContains(t, err, "speaks protocol version 0, expected 1")
+	})
+
+	t.Run("check timeout", func(t *testing.T) {
+		s := NewPluginSentinel(writeFakePlugin(t, dir, "hang"))
+		require.NoError(t, s.Configure(map[string]any{"status": "healthy"}))
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err := s.Check(ctx, "test-alarm")
+		assert.ErrorContains(t, err, "did not answer check")
+	})
+
+	t.Run("missing executable", func(t *testing.T) {
+		s := NewPluginSentinel(filepath.Join(dir, "missing"))
+		assert.Error(t, s.Configure(map[string]any{"status": "healthy"}))
+	})
+}
@@ -0,0 +1,152 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// ProtocolVersion is the version of the JSON-over-stdio protocol spoken with
+// plugin executables. Plugins must echo it in every response.
+const ProtocolVersion = 1
+
+const (
+	MethodDescribe  = "describe"
+	MethodConfigure = "configure"
+	MethodCheck     = "check"
+)
+
+const (
+	// maxMessageSize bounds a single response line.
+	maxMessageSize = 1024 * 1024
+	// processWaitDelay is how long a plugin may take to exit after its stdin
+	// is closed before it is killed.
+	processWaitDelay = 5 * time.Second
+)
+
+// Request is written to the plugin's stdin as a single JSON line.
+type Request struct {
+	ProtocolVersion int            `json:"protocol_version"`
+	Method          string         `json:"method"`
+	Config          map[string]any `json:"config,omitempty"`
+	AlarmID         string         `json:"alarm_id,omitempty"`
+}
+
+// Response is read from the plugin's stdout as a single JSON line.
+type Response struct {
+	ProtocolVersion int                `json:"protocol_version"`
+	Type            string             `json:"type,omitempty"`
+	Status          signal.Status      `json:"status,omitempty"`
+	Message         string             `json:"message,omitempty"`
+	Metrics         map[string]float64 `json:"metrics,omitempty"`
+	Labels          map[string]string  `json:"labels,omitempty"`
+	Error           string             `json:"error,omitempty"`
+}
+
+type process struct {
+	path   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+	killed bool
+}
+
+func startProcess(path string) (*process, error) {
+	cmd := exec.Command(path)
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = processWaitDelay
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %v", path, err)
+	}
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	return &process{path: path, cmd: cmd, stdin: stdin, stdout: scanner}, nil
+}
+
+// call sends a request and waits for its response. The plugin is killed if
+// ctx ends first, since its output can no longer be trusted to be in sync.
+func (p *process) call(ctx context.Context, req Request) (Response, error) {
+	req.ProtocolVersion = ProtocolVersion
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	type result struct {
+		response Response
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if _, err := p.stdin.Write(append(payload, '\n')); err != nil {
+			done <- result{err: fmt.Errorf("failed to write %s request: %v", req.Method, err)}
+			return
+		}
+		if !p.stdout.Scan() {
+			err := p.stdout.Err()
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			done <- result{err: fmt.Errorf("failed to read %s response: %v", req.Method, err)}
+			return
+		}
+		var response Response
+		if err := json.Unmarshal(p.stdout.Bytes(), &response); err != nil {
+			done <- result{err: fmt.Errorf("invalid %s response: %v", req.Method, err)}
+			return
+		}
+		done <- result{response: response}
+	}()
+
+	select {
+	case <-ctx.Done():
+		p.kill()
+		return Response{}, fmt.Errorf("plugin %s did not answer %s: %v", p.path, req.Method, ctx.Err())
+	case r := <-done:
+		if r.err != nil {
+			p.kill()
+			return Response{}, fmt.Errorf("plugin %s: %v", p.path, r.err)
+		}
+		if r.response.ProtocolVersion != ProtocolVersion {
+			p.kill()
+			return Response{}, fmt.Errorf("plugin %s speaks protocol version %d, expected %d", p.path, r.response.ProtocolVersion, ProtocolVersion)
+		}
+		if r.response.Error != "" {
+			return r.response, fmt.Errorf("%s", r.response.Error)
+		}
+		return r.response, nil
+	}
+}
+
+// close ends the session by closing stdin and waits for the plugin to exit.
+func (p *process) close() error {
+	if p.killed {
+		return nil
+	}
+	p.stdin.Close()
+	return p.cmd.Wait()
+}
+
+func (p *process) kill() {
+	if p.killed {
+		return
+	}
+	p.killed = true
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+}
@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+)
+
+// signalDataColumns are the optional JSON columns holding a signal's metrics
+// and labels. They were added after the signals table, so Init adds them to
+// existing tables.
+var signalDataColumns = []string{"metrics", "labels"}
+
+// marshalJSONColumn encodes a map for a nullable JSON column, storing NULL
+// when it is empty.
+func marshalJSONColumn(v any) (sql.NullString, error) {
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Map && value.Len() == 0 {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalJSONColumn decodes a nullable JSON column into v, leaving it
+// untouched when the column is NULL.
+func unmarshalJSONColumn(column sql.NullString, v any) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(column.String), v)
+}
@@ -1,6 +1,9 @@
 package repo
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/g0ulartleo/mirante-alerts/internal/signal"
 )
 
@@ -17,17 +20,25 @@ func (r *MemorySignalRepository) Init() error {
 	return nil
 }
 
-func (r *MemorySignalRepository) Save(signal signal.Signal) error {
-	r.signals[signal.AlarmID] = append(r.signals[signal.AlarmID], signal)
+// Save keeps each alarm's signals sorted by timestamp, oldest first.
+func (r *MemorySignalRepository) Save(sig signal.Signal) error {
+	signals := r.signals[sig.AlarmID]
+	i := sort.Search(len(signals), func(i int) bool {
+		return signals[i].Timestamp.After(sig.Timestamp)
+	})
+	r.signals[sig.AlarmID] = slices.Insert(signals, i, sig)
 	return nil
 }
 
+// GetAlarmLatestSignals returns up to limit signals, newest first.
 func (r *MemorySignalRepository) GetAlarmLatestSignals(alarmID string, limit int) ([]signal.Signal, error) {
 	signals := r.signals[alarmID]
 	if len(signals) == 0 {
 		return nil, nil
 	}
-	return signals[len(signals)-limit:], nil
+	latest := slices.Clone(signals[max(len(signals)-limit, 0):])
+	slices.Reverse(latest)
+	return latest, nil
 }
 
 func (r *MemorySignalRepository) GetAlarmHealth(alarmID string) (signal.Status, error) {
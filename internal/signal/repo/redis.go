@@ -42,7 +42,7 @@ func (r *RedisStore) Save(sig signal.Signal) error {
 	}
 	key := "signals:" + sig.AlarmID
 	if err := r.redis.ZAdd(ctx, key, redis.Z{
-		Score:  float64(sig.Timestamp.Unix()),
+		Score:  float64(sig.Timestamp.UnixMicro()) / 1e6,
 		Member: string(signalJSON),
 	}).Err(); err != nil {
 		return err
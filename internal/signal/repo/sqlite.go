@@ -26,8 +26,16 @@ func NewSQLiteSignalRepository() (signal.SignalRepository, error) {
 }
 
 func (r *SQLiteSignalRepository) Save(signal signal.Signal) error {
-	query := `INSERT INTO signals (alarm_id, status, message, created_at) VALUES (?, ?, ?, ?)`
-	_, err := r.db.Exec(query, signal.AlarmID, signal.Status, signal.Message, time.Now())
+	metrics, err := marshalJSONColumn(signal.Metrics)
+	if err != nil {
+		return err
+	}
+	labels, err := marshalJSONColumn(signal.Labels)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO signals (alarm_id, status, message, metrics, labels, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = r.db.Exec(query, signal.AlarmID, signal.Status, signal.Message, metrics, labels, signal.Timestamp.UTC().Truncate(time.Second))
 	if err != nil {
 		return err
 	}
@@ -36,7 +44,7 @@ func (r *SQLiteSignalRepository) Save(signal signal.Signal) error {
 
 func (r *SQLiteSignalRepository) GetAlarmLatestSignals(alarmID string, limit int) ([]signal.Signal, error) {
 	query := `
-		SELECT alarm_id, status, message, created_at
+		SELECT alarm_id, status, message, metrics, labels, created_at
 		FROM signals WHERE alarm_id = ? ORDER BY created_at DESC LIMIT ?`
 	rows, err := r.db.Query(query, alarmID, limit)
 	if err != nil {
@@ -46,10 +54,17 @@ func (r *SQLiteSignalRepository) GetAlarmLatestSignals(alarmID string, limit int
 	signals := make([]signal.Signal, 0)
 	for rows.Next() {
 		var s signal.Signal
-		err := rows.Scan(&s.AlarmID, &s.Status, &s.Message, &s.Timestamp)
+		var metrics, labels sql.NullString
+		err := rows.Scan(&s.AlarmID, &s.Status, &s.Message, &metrics, &labels, &s.Timestamp)
 		if err != nil {
 			return nil, err
 		}
+		if err := unmarshalJSONColumn(metrics, &s.Metrics); err != nil {
+			return nil, err
+		}
+		if err := unmarshalJSONColumn(labels, &s.Labels); err != nil {
+			return nil, err
+		}
 		signals = append(signals, s)
 	}
 	return signals, nil
@@ -83,12 +98,27 @@ func (r *SQLiteSignalRepository) Init() error {
 		alarm_id VARCHAR(255) NOT NULL,
 		status VARCHAR(255) NOT NULL,
 		message VARCHAR(255) NOT NULL,
+		metrics TEXT,
+		labels TEXT,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	)`
 	_, err := r.db.Exec(query)
 	if err != nil {
 		return err
 	}
+	for _, column := range signalDataColumns {
+		var exists bool
+		query = `SELECT COUNT(*) > 0 FROM pragma_table_info('signals') WHERE name = ?`
+		if err := r.db.QueryRow(query, column).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := r.db.Exec(`ALTER TABLE signals ADD COLUMN ` + column + ` TEXT`); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
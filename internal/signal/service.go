@@ -1,5 +1,16 @@
 package signal
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSignalOutOfOrder is returned when a signal is older than the latest one
+// recorded for its alarm. Signals are kept in timestamp order, so accepting it
+// would leave it behind the alarm's current status.
+var ErrSignalOutOfOrder = errors.New("signal is older than the alarm's latest signal")
+
 type Service struct {
 	repo SignalRepository
 }
@@ -9,9 +20,25 @@ func NewService(repo SignalRepository) *Service {
 }
 
 func (s *Service) WriteSignal(signal Signal) error {
+	if err := s.CheckSignalOrder(signal); err != nil {
+		return err
+	}
 	return s.repo.Save(signal)
 }
 
+// CheckSignalOrder returns ErrSignalOutOfOrder if sig predates the latest
+// signal of its alarm.
+func (s *Service) CheckSignalOrder(sig Signal) error {
+	signals, err := s.GetAlarmLatestSignals(sig.AlarmID, 1)
+	if err != nil {
+		return err
+	}
+	if len(signals) > 0 && sig.Timestamp.Before(signals[0].Timestamp) {
+		return fmt.Errorf("%w from %s", ErrSignalOutOfOrder, signals[0].Timestamp.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
 func (s *Service) GetAlarmLatestSignals(alarmID string, limit int) ([]Signal, error) {
 	return s.repo.GetAlarmLatestSignals(alarmID, limit)
 }
@@ -0,0 +1,49 @@
+package signal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RejectsBackdatedSignals(t *testing.T) {
+	service := signal.NewService(repo.NewMemorySignalRepository())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, service.WriteSignal(signal.Signal{AlarmID: "deploy-pipeline", Status: signal.StatusHealthy, Timestamp: now.Add(-time.Hour)}))
+	require.NoError(t, service.WriteSignal(signal.Signal{AlarmID: "deploy-pipeline", Status: signal.StatusUnhealthy, Timestamp: now}))
+
+	backdated := signal.Signal{AlarmID: "deploy-pipeline", Status: signal.StatusHealthy, Timestamp: now.Add(-time.Minute)}
+	assert.ErrorIs(t, service.CheckSignalOrder(backdated), signal.ErrSignalOutOfOrder)
+	assert.ErrorIs(t, service.WriteSignal(backdated), signal.ErrSignalOutOfOrder)
+
+	latest, err := service.GetAlarmLatestSignals("deploy-pipeline", 1)
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, signal.StatusUnhealthy, latest[0].Status)
+	assert.Equal(t, now, latest[0].Timestamp)
+	changed, err := service.AlarmHasChangedStatus("deploy-pipeline")
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	require.NoError(t, service.WriteSignal(signal.Signal{AlarmID: "deploy-pipeline", Status: signal.StatusUnhealthy, Timestamp: now}))
+	changed, err = service.AlarmHasChangedStatus("deploy-pipeline")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, service.WriteSignal(signal.Signal{AlarmID: "deploy-pipeline", Status: signal.StatusHealthy, Timestamp: now.Add(time.Minute)}))
+	latest, err = service.GetAlarmLatestSignals("deploy-pipeline", 3)
+	require.NoError(t, err)
+	require.Len(t, latest, 3)
+	assert.Equal(t, signal.StatusHealthy, latest[0].Status)
+	changed, err = service.AlarmHasChangedStatus("deploy-pipeline")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	health, err := service.GetAlarmHealth("deploy-pipeline")
+	require.NoError(t, err)
+	assert.Equal(t, signal.StatusHealthy, health)
+}
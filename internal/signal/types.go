@@ -1,18 +1,34 @@
 package signal
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
+// Signal is the outcome of a single check. Metrics holds the numbers behind
+// the message, such as `latency_ms` or `count`, so they can be charted over
+// time, and Labels holds the dimensions they were measured along. Both are
+// optional.
 type Signal struct {
 	AlarmID   string
 	Status    Status
 	Timestamp time.Time
 	Message   string
+	Metrics   map[string]float64 `json:",omitempty"`
+	Labels    map[string]string  `json:",omitempty"`
 }
 
 type Status string
 
 const (
 	StatusHealthy   Status = "healthy"
+	StatusWarning   Status = "warning"
 	StatusUnhealthy Status = "unhealthy"
 	StatusUnknown   Status = "unknown"
 )
+
+var Statuses = []Status{StatusHealthy, StatusWarning, StatusUnhealthy, StatusUnknown}
+
+func (s Status) IsValid() bool {
+	return slices.Contains(Statuses, s)
+}
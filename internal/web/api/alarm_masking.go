@@ -1,11 +1,34 @@
 package api
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
 )
 
+var sensitiveKeyFragments = []string{
+	"password",
+	"token",
+	"secret",
+	"authorization",
+	"cookie",
+	"credential",
+	// "key" alone would also match fields such as the Redis Checker's `keys`,
+	// the S3 Object Checker's `key_regex` or `host_key_fingerprints`, so only
+	// the kinds of keys that are secrets are matched. Names are compared
+	// without separators, so `api_key` and `X-API-Key` both match "apikey".
+	"apikey",
+	"privatekey",
+	"accesskey",
+	"clientkey",
+	"signingkey",
+	"subscriptionkey",
+	"encryptionkey",
+}
+
+var keySeparators = strings.NewReplacer("_", "", "-", "", ".", "", " ", "")
+
 func MaskSensitiveData(a *alarm.Alarm) *alarm.Alarm {
 	maskedAlarm := &alarm.Alarm{
 		ID:            a.ID,
@@ -26,22 +49,41 @@ func MaskSensitiveData(a *alarm.Alarm) *alarm.Alarm {
 }
 
 func maskConfigValue(key string, value any) any {
-	keyLower := strings.ToLower(key)
+	keyLower := keySeparators.Replace(strings.ToLower(key))
 
-	if strings.Contains(keyLower, "password") ||
-		strings.Contains(keyLower, "token") ||
-		strings.Contains(keyLower, "secret") ||
-		strings.Contains(keyLower, "key") {
-		return "****"
+	for _, fragment := range sensitiveKeyFragments {
+		if strings.Contains(keyLower, fragment) {
+			return "****"
+		}
 	}
 
-	if nestedMap, ok := value.(map[string]any); ok {
+	switch v := value.(type) {
+	case map[string]any:
 		maskedMap := make(map[string]any)
-		for nestedKey, nestedValue := range nestedMap {
+		for nestedKey, nestedValue := range v {
 			maskedMap[nestedKey] = maskConfigValue(nestedKey, nestedValue)
 		}
 		return maskedMap
+	case []any:
+		maskedList := make([]any, len(v))
+		for i, item := range v {
+			maskedList[i] = maskConfigValue("", item)
+		}
+		return maskedList
+	case string:
+		return maskURLCredentials(v)
 	}
 
 	return value
 }
+
+func maskURLCredentials(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+	return u.Redacted()
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
 	"github.com/g0ulartleo/mirante-alerts/internal/auth"
@@ -26,6 +29,26 @@ func APIKeyAuthMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// alarmLookupError reports a failed alarm lookup as 404 when the alarm doesn't
+// exist and as 500 for any other error, e.g. the store being unreachable.
+func alarmLookupError(err error, notFound string) *echo.HTTPError {
+	log.Printf("Error fetching alarm: %v", err)
+	if errors.Is(err, alarm.ErrAlarmNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, notFound)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// signalError reports a signal older than its alarm's latest one as 409 and
+// any other failure to record it as 500.
+func signalError(err error, prefix string) *echo.HTTPError {
+	if errors.Is(err, signal.ErrSignalOutOfOrder) {
+		return echo.NewHTTPError(http.StatusConflict, prefix+err.Error())
+	}
+	log.Printf("Error processing signal: %v", err)
+	return echo.NewHTTPError(http.StatusInternalServerError, prefix+err.Error())
+}
+
 func RegisterRoutes(e *echo.Echo, signalService *signal.Service, alarmService *alarm.AlarmService, asyncClient *asynq.Client) {
 	authConfig, err := config.LoadAuthConfig()
 	if err != nil {
@@ -115,6 +138,9 @@ func RegisterRoutes(e *echo.Echo, signalService *signal.Service, alarmService *a
 			log.Printf("Error binding alarm: %v", err)
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
+		if err := alarm.Notifications.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("notifications: %v", err))
+		}
 		if err := alarmService.SetAlarm(alarm); err != nil {
 			log.Printf("Error setting alarm: %v", err)
 			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -122,6 +148,112 @@ func RegisterRoutes(e *echo.Echo, signalService *signal.Service, alarmService *a
 		return c.JSON(http.StatusOK, alarm)
 	})
 
+	api.POST("/alarms/:alarm_id/signals", func(c echo.Context) error {
+		var req signalRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		req.AlarmID = c.Param("alarm_id")
+		sig, err := req.toSignal(time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		alarmConfig, err := alarmService.GetAlarm(sig.AlarmID)
+		if err != nil {
+			return alarmLookupError(err, "Alarm not found")
+		}
+		if err := tasks.ProcessSignal(alarmConfig, sig, signalService, asyncClient); err != nil {
+			return signalError(err, "")
+		}
+		return c.JSON(http.StatusOK, map[string]string{"message": "Signal recorded"})
+	})
+
+	api.POST("/signals", func(c echo.Context) error {
+		var reqs []signalRequest
+		if err := c.Bind(&reqs); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if len(reqs) == 0 || len(reqs) > maxSignalBatchSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected between 1 and %d signals", maxSignalBatchSize))
+		}
+
+		// Validate the whole batch before writing anything so a bad entry
+		// doesn't leave it half applied.
+		now := time.Now()
+		signals := make([]signal.Signal, len(reqs))
+		alarmConfigs := make([]*alarm.Alarm, len(reqs))
+		latest := make(map[string]time.Time)
+		for i, req := range reqs {
+			sig, err := req.toSignal(now)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("signals[%d]: %v", i, err))
+			}
+			alarmConfig, err := alarmService.GetAlarm(sig.AlarmID)
+			if err != nil {
+				return alarmLookupError(err, fmt.Sprintf("signals[%d]: alarm %s not found", i, sig.AlarmID))
+			}
+			if err := signalService.CheckSignalOrder(sig); err != nil {
+				return signalError(err, fmt.Sprintf("signals[%d]: ", i))
+			}
+			if sig.Timestamp.Before(latest[sig.AlarmID]) {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("signals[%d]: %v in the batch", i, signal.ErrSignalOutOfOrder))
+			}
+			latest[sig.AlarmID] = sig.Timestamp
+			signals[i] = sig
+			alarmConfigs[i] = alarmConfig
+		}
+		for i, sig := range signals {
+			if err := tasks.ProcessSignal(alarmConfigs[i], sig, signalService, asyncClient); err != nil {
+				return signalError(err, fmt.Sprintf("signals[%d]: ", i))
+			}
+		}
+		return c.JSON(http.StatusOK, map[string]any{"message": "Signals recorded", "count": len(signals)})
+	})
+
+	api.POST("/heartbeats/:alarm_id", func(c echo.Context) error {
+		alarmID := c.Param("alarm_id")
+		alarmConfig, err := alarmService.GetAlarm(alarmID)
+		if err != nil {
+			return alarmLookupError(err, "Alarm not found")
+		}
+		if !alarmConfig.IsHeartbeat() {
+			return echo.NewHTTPError(http.StatusBadRequest, "Alarm is not a heartbeat alarm")
+		}
+		var ping struct {
+			Status  signal.Status `json:"status"`
+			Message string        `json:"message"`
+		}
+		if err := c.Bind(&ping); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if ping.Status == "" {
+			ping.Status = signal.StatusHealthy
+		}
+		if !ping.Status.IsValid() {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid status")
+		}
+		if ping.Message == "" {
+			ping.Message = "heartbeat received"
+		}
+
+		now := time.Now()
+		if err := alarmService.RecordHeartbeat(alarmID, now); err != nil {
+			log.Printf("Error recording heartbeat: %v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		sig := signal.Signal{
+			AlarmID:   alarmID,
+			Status:    ping.Status,
+			Timestamp: now,
+			Message:   ping.Message,
+		}
+		if err := tasks.ProcessSignal(alarmConfig, sig, signalService, asyncClient); err != nil {
+			log.Printf("Error processing heartbeat signal: %v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(http.StatusOK, map[string]string{"message": "Heartbeat recorded"})
+	})
+
 	api.POST("/alarms/:alarm_id/check", func(c echo.Context) error {
 		alarmID := c.Param("alarm_id")
 		task, err := tasks.NewAlarmCheckTask(alarmID)
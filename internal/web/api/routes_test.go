@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarmLookupError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name:         "alarm not found",
+			err:          fmt.Errorf("%w: deploy-pipeline", alarm.ErrAlarmNotFound),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "store unreachable",
+			err:          errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpErr := alarmLookupError(tt.err, "Alarm not found")
+			assert.Equal(t, tt.expectedCode, httpErr.Code)
+		})
+	}
+}
+
+func TestSignalError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name:         "older than the latest signal",
+			err:          fmt.Errorf("failed to write signal: %w", signal.ErrSignalOutOfOrder),
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "store unreachable",
+			err:          errors.New("failed to write signal: connection refused"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpErr := signalError(tt.err, "signals[0]: ")
+			assert.Equal(t, tt.expectedCode, httpErr.Code)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+)
+
+// maxSignalBatchSize bounds how many signals a single batch request may push.
+const maxSignalBatchSize = 100
+
+// signalRequest is an externally computed signal pushed through the API.
+type signalRequest struct {
+	AlarmID   string             `json:"alarm_id"`
+	Status    signal.Status      `json:"status"`
+	Message   string             `json:"message"`
+	Timestamp *time.Time         `json:"timestamp"`
+	Metrics   map[string]float64 `json:"metrics"`
+	Labels    map[string]string  `json:"labels"`
+}
+
+func (r signalRequest) toSignal(now time.Time) (signal.Signal, error) {
+	if r.AlarmID == "" {
+		return signal.Signal{}, fmt.Errorf("alarm_id is required")
+	}
+	if r.Status == "" {
+		return signal.Signal{}, fmt.Errorf("status is required")
+	}
+	if !r.Status.IsValid() {
+		return signal.Signal{}, fmt.Errorf("invalid status %s, expected one of %v", r.Status, signal.Statuses)
+	}
+	timestamp := now
+	if r.Timestamp != nil {
+		if r.Timestamp.After(now) {
+			return signal.Signal{}, fmt.Errorf("timestamp can't be in the future")
+		}
+		timestamp = *r.Timestamp
+	}
+	return signal.Signal{
+		AlarmID:   r.AlarmID,
+		Status:    r.Status,
+		Timestamp: timestamp,
+		Message:   r.Message,
+		Metrics:   r.Metrics,
+		Labels:    r.Labels,
+	}, nil
+}
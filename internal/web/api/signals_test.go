@@ -0,0 +1,90 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignalRequest_ToSignal(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Minute)
+	later := now.Add(time.Minute)
+
+	tests := []struct {
+		name           string
+		req            signalRequest
+		expectedSignal signal.Signal
+		expectError    bool
+	}{
+		{
+			name: "defaults timestamp to now",
+			req:  signalRequest{AlarmID: "deploy-pipeline", Status: signal.StatusUnhealthy, Message: "smoke tests failed"},
+			expectedSignal: signal.Signal{
+				AlarmID:   "deploy-pipeline",
+				Status:    signal.StatusUnhealthy,
+				Timestamp: now,
+				Message:   "smoke tests failed",
+			},
+		},
+		{
+			name: "keeps given timestamp",
+			req:  signalRequest{AlarmID: "deploy-pipeline", Status: signal.StatusHealthy, Timestamp: &earlier},
+			expectedSignal: signal.Signal{
+				AlarmID:   "deploy-pipeline",
+				Status:    signal.StatusHealthy,
+				Timestamp: earlier,
+			},
+		},
+		{
+			name: "keeps metrics and labels",
+			req: signalRequest{
+				AlarmID: "nightly-e2e",
+				Status:  signal.StatusWarning,
+				Metrics: map[string]float64{"duration_seconds": 312, "failed": 1},
+				Labels:  map[string]string{"branch": "main"},
+			},
+			expectedSignal: signal.Signal{
+				AlarmID:   "nightly-e2e",
+				Status:    signal.StatusWarning,
+				Timestamp: now,
+				Metrics:   map[string]float64{"duration_seconds": 312, "failed": 1},
+				Labels:    map[string]string{"branch": "main"},
+			},
+		},
+		{
+			name:        "missing alarm id",
+			req:         signalRequest{Status: signal.StatusHealthy},
+			expectError: true,
+		},
+		{
+			name:        "missing status",
+			req:         signalRequest{AlarmID: "deploy-pipeline"},
+			expectError: true,
+		},
+		{
+			name:        "invalid status",
+			req:         signalRequest{AlarmID: "deploy-pipeline", Status: "degraded"},
+			expectError: true,
+		},
+		{
+			name:        "timestamp in the future",
+			req:         signalRequest{AlarmID: "deploy-pipeline", Status: signal.StatusHealthy, Timestamp: &later},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := tt.req.toSignal(now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSignal, sig)
+		})
+	}
+}
@@ -16,7 +16,7 @@ func RegisterTasks(mux *asynq.ServeMux, sentinelFactory *sentinel.SentinelFactor
 		return tasks.HandleAlarmCheckTask(ctx, task, sentinelFactory, signalService, alarmService, asyncClient)
 	})
 	mux.HandleFunc(tasks.TypeSignalWrite, func(ctx context.Context, task *asynq.Task) error {
-		return tasks.HandleSignalWriteTask(ctx, task, signalService)
+		return tasks.HandleSignalWriteTask(ctx, task, signalService, alarmService, asyncClient)
 	})
 	mux.HandleFunc(tasks.TypeBackofficeCleanSignals, func(ctx context.Context, task *asynq.Task) error {
 		return tasks.HandleBackofficeCleanSignalsTask(ctx, task, signalService)
@@ -58,6 +58,9 @@ func checkAlarm(
 	if err != nil {
 		return fmt.Errorf("failed to load alarm config: %v: %w", err, asynq.SkipRetry)
 	}
+	if alarmConfig.IsHeartbeat() {
+		return checkHeartbeat(alarmConfig, signalService, alarmService, asyncClient)
+	}
 	sentinel, err := initializeSentinel(alarmConfig, sentinelFactory)
 	if err != nil {
 		writeErr := signalService.WriteSignal(signal.Signal{
@@ -84,11 +87,22 @@ func checkAlarm(
 		return nil
 	}
 	log.Printf("Alarm %s returned signal: %v", payload.AlarmID, sig)
-	err = signalService.WriteSignal(sig)
+	return ProcessSignal(alarmConfig, sig, signalService, asyncClient)
+}
+
+// ProcessSignal writes a signal and, when it changes the alarm's status,
+// notifies the dashboard and the alarm's notification channels.
+func ProcessSignal(
+	alarmConfig *alarm.Alarm,
+	sig signal.Signal,
+	signalService *signal.Service,
+	asyncClient *asynq.Client,
+) error {
+	err := signalService.WriteSignal(sig)
 	if err != nil {
 		return fmt.Errorf("failed to write signal: %w", err)
 	}
-	changed, err := signalService.AlarmHasChangedStatus(payload.AlarmID)
+	changed, err := signalService.AlarmHasChangedStatus(alarmConfig.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get alarm latest signals: %w", err)
 	}
@@ -96,7 +110,7 @@ func checkAlarm(
 		return nil
 	}
 
-	dashboardTask, err := NewDashboardNotifyTask(payload.AlarmID, sig)
+	dashboardTask, err := NewDashboardNotifyTask(alarmConfig.ID, sig)
 	if err != nil {
 		return fmt.Errorf("failed to create dashboard notify task: %w", err)
 	}
@@ -108,7 +122,7 @@ func checkAlarm(
 		if sig.Status == signal.StatusUnknown && !alarmConfig.Notifications.NotifyMissingSignals {
 			return nil
 		}
-		task, err := NewAlarmNotifyTask(payload.AlarmID, sig)
+		task, err := NewAlarmNotifyTask(alarmConfig.ID, sig)
 		if err != nil {
 			return fmt.Errorf("failed to create notify task: %w", err)
 		}
@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/hibiken/asynq"
+)
+
+// checkHeartbeat evaluates the staleness of a heartbeat alarm. Pings record
+// their own signals, so nothing is written while pings keep arriving on time.
+func checkHeartbeat(
+	alarmConfig *alarm.Alarm,
+	signalService *signal.Service,
+	alarmService *alarm.AlarmService,
+	asyncClient *asynq.Client,
+) error {
+	timeout, err := alarmConfig.HeartbeatTimeout()
+	if err != nil {
+		writeErr := signalService.WriteSignal(signal.Signal{
+			AlarmID:   alarmConfig.ID,
+			Status:    signal.StatusUnknown,
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("invalid heartbeat config: %v", err),
+		})
+		if writeErr != nil {
+			return fmt.Errorf("failed to write signal: %w", writeErr)
+		}
+		return fmt.Errorf("invalid heartbeat config: %v: %w", err, asynq.SkipRetry)
+	}
+	lastHeartbeat, err := alarmService.GetLastHeartbeat(alarmConfig.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get last heartbeat: %w", err)
+	}
+
+	sig := evaluateHeartbeat(alarmConfig.ID, lastHeartbeat, timeout, time.Now())
+	if sig == nil {
+		return nil
+	}
+	log.Printf("Heartbeat alarm %s is stale: %v", alarmConfig.ID, *sig)
+	return ProcessSignal(alarmConfig, *sig, signalService, asyncClient)
+}
+
+func evaluateHeartbeat(alarmID string, lastHeartbeat time.Time, timeout time.Duration, now time.Time) *signal.Signal {
+	if lastHeartbeat.IsZero() {
+		return &signal.Signal{
+			AlarmID:   alarmID,
+			Status:    signal.StatusUnknown,
+			Timestamp: now,
+			Message:   "no heartbeat received yet",
+		}
+	}
+	age := now.Sub(lastHeartbeat)
+	if age <= timeout {
+		return nil
+	}
+	return &signal.Signal{
+		AlarmID:   alarmID,
+		Status:    signal.StatusUnhealthy,
+		Timestamp: now,
+		Message: fmt.Sprintf("no heartbeat received for %v, expected one every %v (last at %s)",
+			age.Round(time.Second), timeout, lastHeartbeat.UTC().Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,34 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g0ulartleo/mirante-alerts/internal/signal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateHeartbeat(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("never pinged", func(t *testing.T) {
+		sig := evaluateHeartbeat("nightly-backup", time.Time{}, time.Hour, now)
+		require.NotNil(t, sig)
+		assert.Equal(t, signal.StatusUnknown, sig.Status)
+		assert.Equal(t, "no heartbeat received yet", sig.Message)
+	})
+
+	t.Run("pinged within timeout", func(t *testing.T) {
+		assert.Nil(t, evaluateHeartbeat("nightly-backup", now.Add(-59*time.Minute), time.Hour, now))
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		sig := evaluateHeartbeat("nightly-backup", now.Add(-90*time.Minute), time.Hour, now)
+		require.NotNil(t, sig)
+		assert.Equal(t, "nightly-backup", sig.AlarmID)
+		assert.Equal(t, signal.StatusUnhealthy, sig.Status)
+		assert.Equal(t, now, sig.Timestamp)
+		assert.Equal(t, "no heartbeat received for 1h30m0s, expected one every 1h0m0s (last at 2025-06-01T10:30:00Z)", sig.Message)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/g0ulartleo/mirante-alerts/internal/alarm"
 	"github.com/g0ulartleo/mirante-alerts/internal/signal"
 	"github.com/hibiken/asynq"
 )
@@ -26,12 +27,22 @@ func NewSignalWriteTask(signal signal.Signal) (*asynq.Task, error) {
 	return asynq.NewTask(TypeSignalWrite, payload, asynq.MaxRetry(3)), nil
 }
 
-func HandleSignalWriteTask(ctx context.Context, t *asynq.Task, signalService *signal.Service) error {
+func HandleSignalWriteTask(
+	ctx context.Context,
+	t *asynq.Task,
+	signalService *signal.Service,
+	alarmService *alarm.AlarmService,
+	asyncClient *asynq.Client,
+) error {
 	var p SignalWritePayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
 	}
+	alarmConfig, err := alarmService.GetAlarm(p.Signal.AlarmID)
+	if err != nil {
+		return fmt.Errorf("failed to load alarm config: %v: %w", err, asynq.SkipRetry)
+	}
 
 	log.Printf("Writing signal: signal=%v", p.Signal)
-	return signalService.WriteSignal(p.Signal)
+	return ProcessSignal(alarmConfig, p.Signal, signalService, asyncClient)
 }